@@ -117,6 +117,9 @@ func run(ctx ActionCtx, action interface{}) error {
 	if !ok {
 		return fmt.Errorf("action provided is not an Action")
 	}
+	if err := resolvePrivateKeyFlags(ctx.CurrentCmd()); err != nil {
+		return err
+	}
 	if err := e.SetDefaults(ctx); err != nil {
 		return err
 	}
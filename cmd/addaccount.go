@@ -18,8 +18,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
 	"strings"
 
 	cli "github.com/nats-io/cliprompts/v2"
@@ -45,7 +50,22 @@ func CreateAddAccountCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "account name")
-	cmd.Flags().StringVarP(&params.keyPath, "public-key", "k", "", "public key identifying the account")
+	cmd.Flags().StringVarP(&params.keyPath, "public-key", "k", "", "public key identifying the account - specify '-' to read an account seed from stdin")
+	cmd.Flags().Int64VarP(&params.conns.NumberValue, "conns", "", -1, "set maximum active connections for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.leafConns.NumberValue, "leaf-conns", "", -1, "set maximum active leaf node connections for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.subs.NumberValue, "subs", "", -1, "set maximum subscriptions for the account (-1 is unlimited)")
+	cmd.Flags().StringVarP(&params.data.Value, "data", "", "-1", "set maximum data in bytes for the account (-1 is unlimited) - #, #K, #M or #G")
+	cmd.Flags().StringVarP(&params.payload.Value, "payload", "", "-1", "set maximum message payload in bytes for the account (-1 is unlimited) - #, #K, #M or #G")
+	cmd.Flags().Int64VarP(&params.imports.NumberValue, "imports", "", -1, "set maximum number of imports for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.exports.NumberValue, "exports", "", -1, "set maximum number of exports for the account (-1 is unlimited)")
+	cmd.Flags().BoolVarP(&params.wildcards, "wildcards", "", true, "exports can contain wildcards")
+	cmd.Flags().StringSliceVarP(&params.tags, "tag", "", nil, "tags for the account - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.rmTags, "rm-tag", "", nil, "remove tag, applied after the added tags - comma separated list or option can be specified multiple times")
+	cmd.Flags().BoolVarP(&params.json, "json", "", false, "print the result as json")
+	cmd.Flags().BoolVarP(&params.ifNotExists, "if-not-exists", "", false, "skip adding the account if it already exists instead of failing")
+	cmd.Flags().BoolVarP(&params.withSigningKey, "with-signing-key", "", false, "generate and add an account signing key")
+	cmd.Flags().IntVarP(&params.numSigningKeys, "num-signing-keys", "", 1, "number of signing keys to generate, implies --with-signing-key")
+	cmd.Flags().StringVarP(&params.cloneLimitsFrom, "clone-limits-from", "", "", "copy the limits of the named account, limit flags override the copied limits")
 	params.TimeParams.BindFlags(cmd)
 
 	return cmd
@@ -63,6 +83,44 @@ type AddAccountParams struct {
 	generate bool
 	keyPath  string
 	akp      nkeys.KeyPair
+
+	conns     NumberParams
+	leafConns NumberParams
+	subs      NumberParams
+	imports   NumberParams
+	exports   NumberParams
+	data      DataParams
+	payload   DataParams
+	wildcards bool
+
+	cloneLimitsFrom string
+	cloneLimits     *jwt.OperatorLimits
+
+	ifNotExists bool
+	skip        bool
+
+	tags   []string
+	rmTags []string
+
+	withSigningKey bool
+	numSigningKeys int
+
+	json bool
+}
+
+// addAccountResult is the json rendering of the add account report
+type addAccountResult struct {
+	Name      string             `json:"name"`
+	Subject   string             `json:"subject"`
+	KeyPath   string             `json:"key_path,omitempty"`
+	Generated bool               `json:"generated"`
+	Skipped   bool               `json:"skipped,omitempty"`
+	Messages  []addAccountStatus `json:"messages,omitempty"`
+}
+
+type addAccountStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
 }
 
 func (p *AddAccountParams) SetDefaults(ctx ActionCtx) error {
@@ -71,6 +129,9 @@ func (p *AddAccountParams) SetDefaults(ctx ActionCtx) error {
 		p.name = GetRandomName(0)
 	}
 	p.generate = p.keyPath == ""
+	if ctx.CurrentCmd().Flags().Changed("num-signing-keys") {
+		p.withSigningKey = true
+	}
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
 	return nil
 }
@@ -88,7 +149,7 @@ func (p *AddAccountParams) resolveAccountNKey(s string) (nkeys.KeyPair, error) {
 		return nil, err
 	}
 	if t != nkeys.PrefixByteAccount {
-		return nil, errors.New("specified key is not a valid account nkey")
+		return nil, errors.New("provided public key is not an account key")
 	}
 	return nk, nil
 }
@@ -123,8 +184,33 @@ func (p *AddAccountParams) PreInteractive(ctx ActionCtx) error {
 	return nil
 }
 
+// accountExists returns true if an account with the name exists, names are
+// compared ignoring case
+func (p *AddAccountParams) accountExists() (bool, error) {
+	names, err := GetConfig().ListAccounts()
+	if err != nil {
+		return false, err
+	}
+	lcn := strings.ToLower(p.name)
+	for _, v := range names {
+		if lcn == strings.ToLower(v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (p *AddAccountParams) Load(ctx ActionCtx) error {
 	var err error
+	if p.ifNotExists && p.name != "" {
+		if p.skip, err = p.accountExists(); err != nil {
+			return err
+		}
+	}
+	if p.skip && p.generate {
+		// the account won't be added, don't generate a key for it
+		return nil
+	}
 	if p.generate {
 		p.akp, err = nkeys.CreateAccount()
 		if err != nil {
@@ -133,6 +219,10 @@ func (p *AddAccountParams) Load(ctx ActionCtx) error {
 		if p.keyPath, err = ctx.StoreCtx().KeyStore.Store(p.akp); err != nil {
 			return err
 		}
+	} else if p.keyPath == "-" {
+		if p.akp, err = p.readAccountSeed(os.Stdin); err != nil {
+			return err
+		}
 	} else {
 		p.akp, err = p.resolveAccountNKey(p.keyPath)
 		if err != nil {
@@ -143,6 +233,31 @@ func (p *AddAccountParams) Load(ctx ActionCtx) error {
 	return nil
 }
 
+// readAccountSeed reads an account seed from r, the seed is only kept
+// in memory so it never ends up on the command line or on disk
+func (p *AddAccountParams) readAccountSeed(r io.Reader) (nkeys.KeyPair, error) {
+	d, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the account seed from stdin: %v", err)
+	}
+	seed := strings.TrimSpace(string(d))
+	if seed == "" {
+		return nil, errors.New("an account seed is required on stdin")
+	}
+	kp, err := nkeys.FromSeed([]byte(seed))
+	if err != nil {
+		return nil, fmt.Errorf("stdin doesn't contain a valid seed: %v", err)
+	}
+	t, err := store.KeyType(kp)
+	if err != nil {
+		return nil, err
+	}
+	if t != nkeys.PrefixByteAccount {
+		return nil, errors.New("the seed read from stdin is not an account seed")
+	}
+	return kp, nil
+}
+
 func (p *AddAccountParams) validSigners(ctx ActionCtx) ([]string, error) {
 	oc, err := ctx.StoreCtx().Store.ReadOperatorClaim()
 	if err != nil {
@@ -180,39 +295,55 @@ func (p *AddAccountParams) Validate(ctx ActionCtx) error {
 		p.name = GetRandomName(0)
 	}
 
-	names, err := GetConfig().ListAccounts()
-	if err != nil {
-		return err
-	}
-	found := false
-	lcn := strings.ToLower(p.name)
-	for _, v := range names {
-		if lcn == strings.ToLower(v) {
-			found = true
-			break
+	if !p.skip {
+		found, err := p.accountExists()
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("the account %q already exists", p.name)
 		}
-	}
-	if found {
-		return fmt.Errorf("the account %q already exists", p.name)
 	}
 
-	if p.akp == nil {
+	// when skipping a generated key is not created
+	if p.akp == nil && !(p.skip && p.generate) {
 		return errors.New("path to an account nkey or nkey is required - specify --public-key")
 	}
 
-	kt, err := store.KeyType(p.akp)
-	if err != nil {
-		return err
-	}
+	if p.akp != nil {
+		kt, err := store.KeyType(p.akp)
+		if err != nil {
+			return err
+		}
 
-	if kt != nkeys.PrefixByteAccount {
-		return errors.New("invalid account key")
+		if kt != nkeys.PrefixByteAccount {
+			return errors.New("invalid account key")
+		}
 	}
 
 	if err = p.TimeParams.Validate(); err != nil {
 		return err
 	}
 
+	if err = p.validateLimits(); err != nil {
+		return err
+	}
+
+	if p.withSigningKey && p.numSigningKeys < 1 {
+		return fmt.Errorf("--num-signing-keys must be at least 1 - got %d", p.numSigningKeys)
+	}
+
+	if p.cloneLimitsFrom != "" {
+		if !ctx.StoreCtx().Store.HasAccount(p.cloneLimitsFrom) {
+			return fmt.Errorf("account %q to clone limits from doesn't exist", p.cloneLimitsFrom)
+		}
+		src, err := ctx.StoreCtx().Store.ReadAccountClaim(p.cloneLimitsFrom)
+		if err != nil {
+			return err
+		}
+		p.cloneLimits = &src.Limits
+	}
+
 	// the account doesn't exist, so insure self signed works
 	p.SignerParams.ForceManagedAccountKey(ctx, p.akp)
 	if err := p.SignerParams.Resolve(ctx); err != nil {
@@ -233,7 +364,81 @@ func (p *AddAccountParams) Validate(ctx ActionCtx) error {
 	return nil
 }
 
+// validateLimits parses the sizes and checks that the limits are
+// either -1 (unlimited) or positive
+func (p *AddAccountParams) validateLimits() error {
+	var err error
+	if p.data.Number, err = p.data.NumberValue(); err != nil {
+		return fmt.Errorf("error parsing %s: %s", "data", p.data.Value)
+	}
+	if p.payload.Number, err = p.payload.NumberValue(); err != nil {
+		return fmt.Errorf("error parsing %s: %s", "payload", p.payload.Value)
+	}
+	limits := []struct {
+		name  string
+		value int64
+	}{
+		{"conns", p.conns.NumberValue},
+		{"leaf-conns", p.leafConns.NumberValue},
+		{"subs", p.subs.NumberValue},
+		{"data", p.data.Number},
+		{"payload", p.payload.Number},
+		{"imports", p.imports.NumberValue},
+		{"exports", p.exports.NumberValue},
+	}
+	for _, l := range limits {
+		if l.value < -1 {
+			return fmt.Errorf("%s must be -1 (unlimited) or a positive number - got %d", l.name, l.value)
+		}
+	}
+	return nil
+}
+
+// setLimits applies the cloned limits and then the limits specified as flags to the account
+func (p *AddAccountParams) setLimits(ctx ActionCtx, ac *jwt.AccountClaims) {
+	if p.cloneLimits != nil {
+		ac.Limits = *p.cloneLimits
+	}
+	flags := ctx.CurrentCmd().Flags()
+	if flags.Changed("conns") {
+		ac.Limits.Conn = p.conns.NumberValue
+	}
+	if flags.Changed("leaf-conns") {
+		ac.Limits.LeafNodeConn = p.leafConns.NumberValue
+	}
+	if flags.Changed("subs") {
+		ac.Limits.Subs = p.subs.NumberValue
+	}
+	if flags.Changed("data") {
+		ac.Limits.Data = p.data.Number
+	}
+	if flags.Changed("payload") {
+		ac.Limits.Payload = p.payload.Number
+	}
+	if flags.Changed("imports") {
+		ac.Limits.Imports = p.imports.NumberValue
+	}
+	if flags.Changed("exports") {
+		ac.Limits.Exports = p.exports.NumberValue
+	}
+	if flags.Changed("wildcards") {
+		ac.Limits.WildcardExports = p.wildcards
+	}
+}
+
 func (p *AddAccountParams) Run(ctx ActionCtx) (store.Status, error) {
+	if p.skip {
+		r := store.NewDetailedReport(false)
+		r.AddOK("account %q already exists, skipping", p.name)
+		if p.json {
+			ac, err := ctx.StoreCtx().Store.ReadAccountClaim(p.name)
+			if err != nil {
+				return nil, err
+			}
+			return p.jsonReport(ctx, r, ac.Subject)
+		}
+		return r, nil
+	}
 	var err error
 	pk, err := p.akp.PublicKey()
 	if err != nil {
@@ -250,6 +455,20 @@ func (p *AddAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 		ac.Expires, _ = p.TimeParams.ExpiryDate()
 	}
 
+	p.setLimits(ctx, ac)
+
+	// the jwt library lower cases and de-duplicates tags
+	ac.Tags.Add(p.tags...)
+	ac.Tags.Remove(p.rmTags...)
+	sort.Strings(ac.Tags)
+
+	var skPaths []string
+	if p.withSigningKey {
+		if skPaths, err = p.addSigningKeys(ctx, ac); err != nil {
+			return nil, err
+		}
+	}
+
 	signer := p.akp
 	if !ctx.StoreCtx().Store.IsManaged() || p.signerKP != nil {
 		signer = p.signerKP
@@ -263,9 +482,83 @@ func (p *AddAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 	if p.generate {
 		r.AddOK("generated and stored account key %q", pk)
 	}
+	for _, fp := range skPaths {
+		r.AddOK("generated and stored account signing key %q", AbbrevHomePaths(fp))
+	}
+	if p.cloneLimits != nil {
+		r.AddOK("cloned limits from account %q", p.cloneLimitsFrom)
+	}
 	StoreAccountAndUpdateStatus(ctx, p.token, r)
 	if r.HasNoErrors() {
 		r.AddOK("added account %q", p.name)
 	}
+	if p.json {
+		return p.jsonReport(ctx, r, pk)
+	}
 	return r, err
 }
+
+// addSigningKeys generates the requested number of signing keys, stores
+// their seeds and adds the public keys to the account, the stored key
+// paths are returned
+func (p *AddAccountParams) addSigningKeys(ctx ActionCtx, ac *jwt.AccountClaims) ([]string, error) {
+	var paths []string
+	for i := 0; i < p.numSigningKeys; i++ {
+		kp, err := nkeys.CreateAccount()
+		if err != nil {
+			return nil, err
+		}
+		pk, err := kp.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		fp, err := ctx.StoreCtx().KeyStore.Store(kp)
+		if err != nil {
+			return nil, err
+		}
+		ac.SigningKeys.Add(pk)
+		paths = append(paths, fp)
+	}
+	return paths, nil
+}
+
+// jsonReport writes the report as json to stdout, the report is
+// not returned as it was already rendered
+func (p *AddAccountParams) jsonReport(ctx ActionCtx, r *store.Report, pk string) (store.Status, error) {
+	res := addAccountResult{Name: p.name, Subject: pk, Generated: p.generate && !p.skip, Skipped: p.skip}
+	ks := ctx.StoreCtx().KeyStore
+	if ks.HasPrivateKey(pk) {
+		res.KeyPath = ks.GetKeyPath(pk)
+	}
+	for _, d := range r.Details {
+		m := d.Message()
+		if dr := store.ToReport(d); dr != nil {
+			m = dr.Label
+		}
+		res.Messages = append(res.Messages, addAccountStatus{Status: statusName(d.Code()), Message: m})
+	}
+	d, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	d = append(d, '\n')
+	if err := Write("--", d); err != nil {
+		return nil, err
+	}
+	if r.HasErrors() {
+		return nil, fmt.Errorf("failed to add account %q", p.name)
+	}
+	return nil, nil
+}
+
+func statusName(code store.StatusCode) string {
+	switch code {
+	case store.OK:
+		return "ok"
+	case store.WARN:
+		return "warning"
+	case store.ERR:
+		return "error"
+	}
+	return "none"
+}
@@ -18,7 +18,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nkeys"
@@ -43,7 +48,7 @@ func Test_AddAccount(t *testing.T) {
 		{CreateAddAccountCmd(), []string{"add", "account", "--name", "B", "--public-key", bar}, nil, nil, false},
 		{CreateAddAccountCmd(), []string{"add", "account", "--name", "*"}, nil, []string{"generated and stored account key", "added account"}, false},
 		{CreateAddAccountCmd(), []string{"add", "account", "--name", "*"}, nil, []string{"generated and stored account key", "added account"}, false}, // should make a new name
-		{CreateAddAccountCmd(), []string{"add", "account", "--name", "X", "--public-key", cpk}, nil, []string{"specified key is not a valid account nkey"}, true},
+		{CreateAddAccountCmd(), []string{"add", "account", "--name", "X", "--public-key", cpk}, nil, []string{"provided public key is not an account key"}, true},
 		{CreateAddAccountCmd(), []string{"add", "account", "--name", "badexp", "--expiry", "30d"}, nil, nil, false},
 	}
 
@@ -159,3 +164,245 @@ func Test_AddAccountNameArg(t *testing.T) {
 	_, err = ts.Store.ReadAccountClaim("A")
 	require.NoError(t, err)
 }
+
+func Test_AddAccountLimits(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--conns", "100", "--leaf-conns", "5", "--payload", "1K", "--wildcards=false")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), ac.Limits.Conn)
+	require.Equal(t, int64(5), ac.Limits.LeafNodeConn)
+	require.Equal(t, int64(1000), ac.Limits.Payload)
+	require.False(t, ac.Limits.WildcardExports)
+	// limits not specified remain unlimited
+	require.Equal(t, int64(jwt.NoLimit), ac.Limits.Subs)
+	require.Equal(t, int64(jwt.NoLimit), ac.Limits.Data)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--subs", "-2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "subs must be -1 (unlimited) or a positive number")
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--data", "lots")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error parsing data")
+}
+
+func Test_AddAccountCloneLimits(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--conns", "100", "--leaf-conns", "5", "--data", "1M", "--wildcards=false")
+	require.NoError(t, err)
+	src, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--clone-limits-from", "A")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Equal(t, src.Limits, ac.Limits)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "C", "--clone-limits-from", "A", "--conns", "7")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("C")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), ac.Limits.Conn)
+	require.Equal(t, src.Limits.LeafNodeConn, ac.Limits.LeafNodeConn)
+	require.Equal(t, src.Limits.Data, ac.Limits.Data)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "D", "--clone-limits-from", "X")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `account "X" to clone limits from doesn't exist`)
+}
+
+func Test_AddAccountIfNotExists(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--if-not-exists")
+	require.NoError(t, err)
+	before, err := ts.Store.ReadRawAccountClaim("A")
+	require.NoError(t, err)
+	keys, err := ts.KeyStore.AllKeys()
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--if-not-exists")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `account "A" already exists, skipping`)
+	after, err := ts.Store.ReadRawAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+	// no key was generated for the skipped account
+	keys2, err := ts.KeyStore.AllKeys()
+	require.NoError(t, err)
+	require.Equal(t, len(keys), len(keys2))
+
+	// other validation errors are still reported - an account key cannot sign an account
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddAccountCmd()), "A", "--if-not-exists", "-K", ts.GetAccountKeyPath(t, "A"))
+	require.Error(t, err)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "A")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `the account "A" already exists`)
+}
+
+func Test_AddAccountTags(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--tag", "prod,team-a", "--tag", "PROD")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, jwt.TagList{"prod", "team-a"}, ac.Tags)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--tag", "prod,team-a", "--rm-tag", "Team-A")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Equal(t, jwt.TagList{"prod"}, ac.Tags)
+}
+
+func Test_AddAccountJSON(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	stdout, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--json")
+	require.NoError(t, err)
+	var res addAccountResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &res))
+	require.Equal(t, "A", res.Name)
+	require.True(t, res.Generated)
+	require.False(t, res.Skipped)
+
+	// the subject is the public key of the generated and stored nkey
+	kp, err := store.ResolveKey(res.KeyPath)
+	require.NoError(t, err)
+	pk, err := kp.PublicKey()
+	require.NoError(t, err)
+	require.Equal(t, pk, res.Subject)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), res.Subject)
+	require.Equal(t, "ok", res.Messages[len(res.Messages)-1].Status)
+
+	stdout, _, err = ExecuteCmd(CreateAddAccountCmd(), "A", "--json", "--if-not-exists")
+	require.NoError(t, err)
+	res = addAccountResult{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &res))
+	require.True(t, res.Skipped)
+	require.False(t, res.Generated)
+	require.Equal(t, pk, res.Subject)
+}
+
+func Test_AddAccountWithSigningKeys(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, stderr, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--num-signing-keys", "2")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 2)
+	for _, pk := range ac.SigningKeys {
+		require.True(t, ts.KeyStore.HasPrivateKey(pk))
+		require.Contains(t, stderr, AbbrevHomePaths(ts.KeyStore.GetKeyPath(pk)))
+	}
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--with-signing-key")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 1)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "C", "--num-signing-keys", "0")
+	require.Error(t, err)
+}
+
+func Test_AddAccountOperatorPublicKey(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	opk, err := ts.OperatorKey.PublicKey()
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "A", "--public-key", opk)
+	require.Error(t, err)
+	require.Equal(t, "provided public key is not an account key", err.Error())
+	require.False(t, ts.Store.HasAccount("A"))
+}
+
+// withStdin runs fn with os.Stdin reading the specified data
+func withStdin(t *testing.T, data string, fn func()) {
+	fp := filepath.Join(MakeTempDir(t), "stdin")
+	require.NoError(t, ioutil.WriteFile(fp, []byte(data), 0600))
+	f, err := os.Open(fp)
+	require.NoError(t, err)
+	defer f.Close()
+
+	old := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = old }()
+	fn()
+}
+
+func Test_AddAccountSeedFromStdin(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	akp, err := nkeys.CreateAccount()
+	require.NoError(t, err)
+	seed, err := akp.Seed()
+	require.NoError(t, err)
+	apk, err := akp.PublicKey()
+	require.NoError(t, err)
+
+	withStdin(t, string(seed)+"\n", func() {
+		_, _, err = ExecuteCmd(CreateAddAccountCmd(), "A", "--public-key", "-")
+	})
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, apk, ac.Subject)
+	// the seed is only used, it is not stored
+	require.False(t, ts.KeyStore.HasPrivateKey(apk))
+
+	ukp, err := nkeys.CreateUser()
+	require.NoError(t, err)
+	useed, err := ukp.Seed()
+	require.NoError(t, err)
+	withStdin(t, string(useed)+"\n", func() {
+		_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B", "--public-key", "-")
+	})
+	require.Error(t, err)
+	require.Equal(t, "the seed read from stdin is not an account seed", err.Error())
+	require.False(t, ts.Store.HasAccount("B"))
+
+	withStdin(t, apk+"\n", func() {
+		_, _, err = ExecuteCmd(CreateAddAccountCmd(), "C", "--public-key", "-")
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stdin doesn't contain a valid seed")
+	require.False(t, ts.Store.HasAccount("C"))
+}
+
+func Test_AddAccountExpiry(t *testing.T) {
+	ts := NewTestStore(t, "add_account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--expiry", "90d")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	expected := time.Now().AddDate(0, 0, 90).Unix()
+	require.InDelta(t, expected, ac.Expires, 5)
+	require.Zero(t, ac.NotBefore)
+
+	_, _, err = ExecuteCmd(CreateAddAccountCmd(), "B")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Zero(t, ac.Expires)
+	require.Zero(t, ac.NotBefore)
+}
@@ -230,6 +230,11 @@ func (p *AddExportParams) Validate(ctx ActionCtx) error {
 		return errors.New("a subject is required")
 	}
 
+	// accept any case, unknown response types fail the export validation
+	if rt, err := parseResponseType(p.responseType); p.service && err == nil {
+		p.export.ResponseType = rt
+	}
+
 	// get the old validation results
 	var vr jwt.ValidationResults
 	if err = p.claim.Exports.Validate(&vr); err != nil {
@@ -268,16 +273,6 @@ func (p *AddExportParams) Validate(ctx ActionCtx) error {
 		return errors.New(uvr.Issues[0].Error())
 	}
 
-	if p.service {
-		rt := jwt.ResponseType(p.responseType)
-		if rt != jwt.ResponseTypeSingleton &&
-			rt != jwt.ResponseTypeStream &&
-			rt != jwt.ResponseTypeChunked {
-			return fmt.Errorf("unknown response type %q", p.responseType)
-		}
-		p.export.ResponseType = rt
-	}
-
 	if err = p.SignerParams.Resolve(ctx); err != nil {
 		return err
 	}
@@ -302,3 +297,14 @@ func (p *AddExportParams) Run(ctx ActionCtx) (store.Status, error) {
 	}
 	return r, err
 }
+
+// parseResponseType returns the service response type matching the name,
+// the name is not case sensitive
+func parseResponseType(name string) (jwt.ResponseType, error) {
+	for _, rt := range []jwt.ResponseType{jwt.ResponseTypeSingleton, jwt.ResponseTypeStream, jwt.ResponseTypeChunked} {
+		if strings.EqualFold(name, string(rt)) {
+			return rt, nil
+		}
+	}
+	return "", fmt.Errorf("unknown response type %q", name)
+}
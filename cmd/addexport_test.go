@@ -226,3 +226,15 @@ func TestAddServiceLatencyInteractive(t *testing.T) {
 	require.Equal(t, 100, ac.Exports[0].Latency.Sampling)
 	require.EqualValues(t, jwt.ResponseTypeStream, ac.Exports[0].ResponseType)
 }
+
+func Test_AddExportResponseTypeCaseInsensitive(t *testing.T) {
+	ts := NewTestStore(t, "add_export")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(createAddExportCmd(), "--subject", "q", "--service", "--response-type", "chunked")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.EqualValues(t, jwt.ResponseTypeChunked, ac.Exports[0].ResponseType)
+}
@@ -43,7 +43,7 @@ func createAddImportCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&params.tokenSrc, "token", "u", "", "path to token file can be a local path or an url (private imports only)")
 
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "import name")
-	cmd.Flags().StringVarP(&params.local, "local-subject", "s", "", "local subject or prefix")
+	cmd.Flags().StringVarP(&params.local, "local-subject", "s", "", "local subject or prefix - a stream can also be remapped to the remote subject with a prefix, ie partner.orders.> for orders.>")
 	params.srcAccount.BindFlags("src-account", "", nkeys.PrefixByteAccount, cmd)
 	cmd.Flags().StringVarP(&params.remote, "remote-subject", "", "", "remote subject (only public imports)")
 	cmd.Flags().BoolVarP(&params.service, "service", "", false, "service (only public imports)")
@@ -473,6 +473,14 @@ func (p *AddImportParams) Validate(ctx ActionCtx) error {
 		kind = jwt.Service
 	}
 
+	// a stream can be remapped to a local subject that is the remote
+	// subject with a prefix, the import stores the prefix
+	if kind == jwt.Stream {
+		if prefix, ok := streamImportPrefix(p.local, p.remote); ok {
+			p.local = prefix
+		}
+	}
+
 	// local becomes Subject for services, or prefix for streams
 	sub := jwt.Subject(p.local)
 	if sub.HasWildCards() {
@@ -506,6 +514,26 @@ func (p *AddImportParams) Validate(ctx ActionCtx) error {
 	return nil
 }
 
+// streamImportPrefix returns the prefix if local is a wildcard subject that
+// is the remote subject with a prefix, ie orders.> as partner.orders.>
+func streamImportPrefix(local string, remote string) (string, bool) {
+	if !jwt.Subject(local).HasWildCards() {
+		return "", false
+	}
+	lt := strings.Split(local, ".")
+	rt := strings.Split(remote, ".")
+	n := len(lt) - len(rt)
+	if n < 1 {
+		return "", false
+	}
+	for i, t := range rt {
+		if lt[n+i] != t {
+			return "", false
+		}
+	}
+	return strings.Join(lt[:n], "."), true
+}
+
 func (p *AddImportParams) filter(kind jwt.ExportType, imports jwt.Imports) jwt.Imports {
 	var buf jwt.Imports
 	for _, v := range imports {
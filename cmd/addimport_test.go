@@ -489,6 +489,32 @@ func Test_ImportStreamLocalWildcards(t *testing.T) {
 	require.Contains(t, "stream prefix subject cannot have wildcards", err.Error())
 }
 
+func Test_ImportStreamLocalSubjectRemap(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Stream, "orders.>", true)
+	apk := ts.GetAccountPublicKey(t, "A")
+
+	ts.AddAccount(t, "B")
+	_, _, err := ExecuteCmd(createAddImportCmd(), "--account", "B", "--src-account", apk, "--remote-subject", "orders.>", "--local-subject", "partner.orders.>")
+	require.NoError(t, err)
+
+	token, err := ts.Store.ReadRawAccountClaim("B")
+	require.NoError(t, err)
+	ac, err := jwt.DecodeAccountClaims(string(token))
+	require.NoError(t, err)
+	require.Len(t, ac.Imports, 1)
+	require.Equal(t, jwt.Subject("orders.>"), ac.Imports[0].Subject)
+	require.Equal(t, jwt.Subject("partner"), ac.Imports[0].To)
+
+	// the local subject has to be the remote subject with a prefix
+	_, _, err = ExecuteCmd(createAddImportCmd(), "--account", "B", "--src-account", apk, "--remote-subject", "orders.>", "--local-subject", "partner.*.>", "--name", "other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stream prefix subject cannot have wildcards")
+}
+
 func Test_AddImportToAccount(t *testing.T) {
 	ts := NewTestStore(t, t.Name())
 	defer ts.Done(t)
@@ -45,6 +45,7 @@ func createAddOperatorCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "operator name")
 	cmd.Flags().StringVarP(&params.jwtPath, "url", "u", "", "import from a jwt server url, file, or well known operator")
+	cmd.Flags().BoolVarP(&params.setupSys, "setup-system-account", "", false, "also create a system account with a sys user and its creds")
 	params.TimeParams.BindFlags(cmd)
 
 	return cmd
@@ -62,14 +63,20 @@ type AddOperatorParams struct {
 	name     string
 	generate bool
 	keyPath  string
+	setupSys bool
 }
 
+const (
+	systemAccountName = "SYS"
+	systemUserName    = "sys"
+)
+
 func (p *AddOperatorParams) SetDefaults(ctx ActionCtx) error {
 	p.name = NameFlagOrArgument(p.name, ctx)
 	if p.name == "*" {
 		p.name = GetRandomName(0)
 	}
-	p.generate = KeyPathFlag == ""
+	p.generate = KeyPathFlag == "" && keyFileKP == nil
 	p.keyPath = KeyPathFlag
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, false, ctx)
 
@@ -210,6 +217,10 @@ func (p *AddOperatorParams) PostInteractive(ctx ActionCtx) error {
 
 func (p *AddOperatorParams) Validate(ctx ActionCtx) error {
 	var err error
+	if p.setupSys && p.jwtPath != "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("--setup-system-account cannot be used with an imported operator")
+	}
 	if p.token != "" {
 		// validated on load
 		return nil
@@ -233,7 +244,12 @@ func (p *AddOperatorParams) Validate(ctx ActionCtx) error {
 		}
 	}
 
-	if p.keyPath != "" {
+	if keyFileKP != nil {
+		if !store.KeyPairTypeOk(nkeys.PrefixByteOperator, keyFileKP) {
+			return errors.New("specified key is not a valid operator nkey")
+		}
+		p.signerKP = keyFileKP
+	} else if p.keyPath != "" {
 		p.signerKP, err = p.resolveOperatorNKey(p.keyPath)
 		if err != nil {
 			return err
@@ -310,5 +326,77 @@ func (p *AddOperatorParams) Run(_ ActionCtx) (store.Status, error) {
 		}
 		r.AddOK("%s operator %q", verb, p.name)
 	}
+	if err == nil && p.setupSys {
+		if err := p.setupSystemAccount(s, r); err != nil {
+			r.AddFromError(err)
+			return r, err
+		}
+	}
 	return r, err
 }
+
+// setupSystemAccount creates a system account signed by the operator
+// and a sys user with stored creds
+func (p *AddOperatorParams) setupSystemAccount(s *store.Store, r *store.Report) error {
+	ctx, err := s.GetContext()
+	if err != nil {
+		return err
+	}
+	akp, err := nkeys.CreateAccount()
+	if err != nil {
+		return err
+	}
+	apk, err := akp.PublicKey()
+	if err != nil {
+		return err
+	}
+	if _, err := ctx.KeyStore.Store(akp); err != nil {
+		return err
+	}
+	ac := jwt.NewAccountClaims(apk)
+	ac.Name = systemAccountName
+	token, err := ac.Encode(p.signerKP)
+	if err != nil {
+		return err
+	}
+	if _, err := s.StoreClaim([]byte(token)); err != nil {
+		return err
+	}
+	r.AddOK("added system account %q", systemAccountName)
+
+	ukp, err := nkeys.CreateUser()
+	if err != nil {
+		return err
+	}
+	upk, err := ukp.PublicKey()
+	if err != nil {
+		return err
+	}
+	if _, err := ctx.KeyStore.Store(ukp); err != nil {
+		return err
+	}
+	uc := jwt.NewUserClaims(upk)
+	uc.Name = systemUserName
+	token, err = uc.Encode(akp)
+	if err != nil {
+		return err
+	}
+	if _, err := s.StoreClaim([]byte(token)); err != nil {
+		return err
+	}
+	r.AddOK("added user %q to account %q", systemUserName, systemAccountName)
+
+	d, err := GenerateConfig(s, systemAccountName, systemUserName, ukp)
+	if err != nil {
+		return err
+	}
+	fp, err := ctx.KeyStore.MaybeStoreUserCreds(systemAccountName, systemUserName, d)
+	if err != nil {
+		return err
+	}
+	r.AddOK("generated user creds file %q", AbbrevHomePaths(fp))
+	// the operator jwt doesn't carry the system account, the server config does
+	r.Add(store.NewServerMessage(fmt.Sprintf("to use %q as the system account, enter:\n  %s generate config --mem-resolver --sys-account %s",
+		systemAccountName, GetToolName(), systemAccountName)))
+	return nil
+}
@@ -18,6 +18,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -237,3 +238,36 @@ func Test_AddOperatorNameArg(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "X", oc.Name)
 }
+
+func Test_AddOperatorSetupSystemAccount(t *testing.T) {
+	ts := NewEmptyStore(t)
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(createAddOperatorCmd(), "--name", "O", "--setup-system-account")
+	require.NoError(t, err)
+
+	s, err := GetConfig().LoadStore("O")
+	require.NoError(t, err)
+	oc, err := s.ReadOperatorClaim()
+	require.NoError(t, err)
+
+	ac, err := s.ReadAccountClaim("SYS")
+	require.NoError(t, err)
+	require.Equal(t, oc.Subject, ac.Issuer)
+
+	uc, err := s.ReadUserClaim("SYS", "sys")
+	require.NoError(t, err)
+	require.Equal(t, ac.Subject, uc.Issuer)
+
+	ctx, err := s.GetContext()
+	require.NoError(t, err)
+	fp := ctx.KeyStore.GetUserCredsPath("SYS", "sys")
+	require.NotEmpty(t, fp)
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	token, err := jwt.ParseDecoratedJWT(d)
+	require.NoError(t, err)
+	cuc, err := jwt.DecodeUserClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, uc.Subject, cuc.Subject)
+}
@@ -16,8 +16,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,10 +34,12 @@ import (
 	"github.com/nats-io/nkeys"
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 )
 
 func CreateAddUserCmd() *cobra.Command {
 	var params AddUserParams
+	params.payload.Binary = true
 	cmd := &cobra.Command{
 		Use:          "user",
 		Short:        "Add an user to the account",
@@ -51,6 +59,14 @@ nsc add user --name <n> --deny-pubsub <subject>,...
 nsc add user --name <n> --deny-pub <subject>,...
 nsc add user --name <n> --deny-sub <subject>,...
 
+# Subjects can use the macros the server expands when the user connects,
+# {{name()}}, {{subject()}}, {{account-name()}}, {{tag(<name>)}} and
+# {{account-tag(<name>)}} - unknown macros are rejected:
+nsc add user --name <n> --allow-sub "_INBOX.{{account-name()}}.>"
+
+# To deny everything not explicitly allowed (least-privilege user):
+nsc add user --name <n> --deny-default --allow-pub <subject>,...
+
 # To dynamically allow publishing to reply subjects, this works well for service responders:
 nsc add user --name <n> --allow-pub-response
 
@@ -62,6 +78,9 @@ nsc add user --name <n> --allow-pub-response --response-ttl 5s
 nsc add user --name <n> --allow-pub-response=5
 # See 'nsc edit export --response-type --help' to enable multiple
 # responses between accounts
+
+# Add several users described in a YAML or JSON manifest:
+nsc add user --from-file users.yaml
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return RunAction(cmd, args, &params)
@@ -75,14 +94,28 @@ nsc add user --name <n> --allow-pub-response=5
 	cmd.Flags().StringSliceVarP(&params.denyPubs, "deny-pub", "", nil, "deny publish permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.denyPubsub, "deny-pubsub", "", nil, "deny publish and subscribe permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.denySubs, "deny-sub", "", nil, "deny subscribe permissions - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.denyPubsFile, "pub-deny-from-file", "", "", "deny publish permissions read from a file with a subject per line")
+	cmd.Flags().StringVarP(&params.denySubsFile, "sub-deny-from-file", "", "", "deny subscribe permissions read from a file with a subject per line")
+	cmd.Flags().BoolVarP(&params.denyDefault, "deny-default", "", false, "deny publish and subscribe on all subjects not explicitly allowed")
 
 	cmd.Flags().StringSliceVarP(&params.tags, "tag", "", nil, "tags for user - comma separated list or option can be specified multiple times")
-	cmd.Flags().StringSliceVarP(&params.src, "source-network", "", nil, "source network for connection - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.rmTags, "rm-tag", "", nil, "remove tag, applied after the added tags - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.tagExpiry, "tag-expiry", "", "", "review date for the user (yyyy-mm-dd), stored as a review:<date> tag")
+	cmd.Flags().StringSliceVarP(&params.src, "source-network", "", nil, "source network (CIDR or IP) for connection - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.rmSrc, "rm-source-network", "", nil, "remove source network, applied after the added source networks - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.payload.Value, "payload", "", "-1", "set maximum message payload in bytes for the user (-1 is unlimited) - #, #Kb, #Mb or #Gb (multiples of 1024)")
 
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "name to assign the user")
 	cmd.Flags().StringVarP(&params.keyPath, "public-key", "k", "", "public key identifying the user")
+	cmd.Flags().StringVarP(&params.credsOut, "output-file", "o", "", "write the user creds to the file instead of the keystore, '--' is stdout")
+	cmd.Flags().StringVarP(&params.credsDir, "output-dir", "", "", "write the user creds as <account>.<user>.creds in the directory instead of the keystore (exclusive of --output-file)")
+	cmd.Flags().StringVarP(&params.signingKey, "signing-key", "", "", "account signing key (public key, seed or path) to sign the user with - must be one of the account's signing keys")
+	cmd.Flags().StringVarP(&params.fromFile, "from-file", "", "", "add the users described in a YAML or JSON manifest")
+	cmd.Flags().IntVarP(&params.count, "count", "", 1, "add this many users, {n} in the name is replaced by 1 to the count")
+	cmd.Flags().BoolVarP(&params.dryRun, "dry-run", "", false, "print the user claims and the files that would be written without changing the store or keystore")
 
 	params.TimeParams.BindFlags(cmd)
+	cmd.Flags().StringVarP(&params.validFor, "valid-for", "", "", "expire the user this long after it is issued (exclusive of --expiry) - #m(inutes), #h(ours), #d(ays), #w(eeks), #M(onths), #y(ears)")
 	params.AccountContextParams.BindFlags(cmd)
 	params.ResponsePermsParams.bindSetFlags(cmd)
 
@@ -105,15 +138,51 @@ type AddUserParams struct {
 	denyPubs      []string
 	denyPubsub    []string
 	denySubs      []string
+	denyPubsFile  string
+	denySubsFile  string
+	denyDefault   bool
 	src           []string
+	rmSrc         []string
 	tags          []string
+	rmTags        []string
+	removedTags   jwt.TagList
+	payload       DataParams
 	credsFilePath string
+	credsOut      string
+	credsDir      string
+	validFor      string
+	fromFile      string
+	manifest      []userSpec
+	signingKey    string
+	tagExpiry     string
+	dryRun        bool
+	makeCredsDir  bool
+	count         int
+	batch         []string
+}
+
+// userSpec describes a user in an add user manifest
+type userSpec struct {
+	Name           string   `yaml:"name"`
+	AllowPub       []string `yaml:"allow-pub"`
+	AllowSub       []string `yaml:"allow-sub"`
+	AllowPubsub    []string `yaml:"allow-pubsub"`
+	DenyPub        []string `yaml:"deny-pub"`
+	DenySub        []string `yaml:"deny-sub"`
+	DenyPubsub     []string `yaml:"deny-pubsub"`
+	DenyDefault    bool     `yaml:"deny-default"`
+	Tags           []string `yaml:"tags"`
+	SourceNetworks []string `yaml:"source-networks"`
+	Payload        string   `yaml:"payload"`
+	Start          string   `yaml:"start"`
+	Expiry         string   `yaml:"expiry"`
 }
 
 func (p *AddUserParams) longHelp() string {
 	s := `toolName add user -i
 toolName add user --name u --deny-pubsub "bar.>"
-toolName add user --name u --tag test,service_a`
+toolName add user --name u --tag test,service_a
+toolName add user --name worker-{n} --count 10`
 
 	return strings.Replace(s, "toolName", GetToolName(), -1)
 }
@@ -127,10 +196,51 @@ func (p *AddUserParams) SetDefaults(ctx ActionCtx) error {
 		return err
 	}
 	p.SignerParams.SetDefaults(nkeys.PrefixByteAccount, true, ctx)
+	if p.fromFile != "" {
+		if p.name != "" || p.keyPath != "" || p.credsOut != "" {
+			ctx.CurrentCmd().SilenceUsage = false
+			return errors.New("--from-file is exclusive of --name, --public-key and --output-file")
+		}
+		return nil
+	}
 	p.create = true
 	p.Entity.kind = nkeys.PrefixByteUser
 	p.editFn = p.editUserClaim
 
+	if p.validFor != "" {
+		if ctx.CurrentCmd().Flags().Changed("expiry") {
+			ctx.CurrentCmd().SilenceUsage = false
+			return errors.New("specify only one of --valid-for or --expiry")
+		}
+		if !validForRe.MatchString(p.validFor) {
+			return fmt.Errorf("valid-for %q is invalid - expected a duration such as 90d or 24h", p.validFor)
+		}
+		p.TimeParams.Expiry = p.validFor
+		return nil
+	}
+
+	return p.setDefaultExpiry(ctx)
+}
+
+var validForRe = regexp.MustCompile(`^\d+[mhdwMy]$`)
+
+// setDefaultExpiry applies the account's default user expiry
+// if an expiry was not specified
+func (p *AddUserParams) setDefaultExpiry(ctx ActionCtx) error {
+	if ctx.CurrentCmd().Flags().Changed("expiry") || p.AccountContextParams.Name == "" {
+		return nil
+	}
+	s := ctx.StoreCtx().Store
+	if !s.HasAccount(p.AccountContextParams.Name) {
+		return nil
+	}
+	d, err := s.ReadAccountDefaults(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	if d.UserExpiry != "" {
+		p.TimeParams.Expiry = d.UserExpiry
+	}
 	return nil
 }
 
@@ -153,6 +263,10 @@ func (p *AddUserParams) PreInteractive(ctx ActionCtx) error {
 		return err
 	}
 
+	if err = p.payload.Edit("max payload (-1 unlimited)"); err != nil {
+		return err
+	}
+
 	if err = p.SignerParams.Edit(ctx); err != nil {
 		return err
 	}
@@ -170,6 +284,12 @@ func (p *AddUserParams) PostInteractive(_ ActionCtx) error {
 
 func (p *AddUserParams) Validate(ctx ActionCtx) error {
 	var err error
+	if p.fromFile != "" {
+		if p.dryRun {
+			return errors.New("--dry-run is not supported with --from-file")
+		}
+		return p.validateManifest(ctx)
+	}
 	if p.name == "" {
 		ctx.CurrentCmd().SilenceUsage = false
 		return fmt.Errorf("user name is required")
@@ -183,6 +303,10 @@ func (p *AddUserParams) Validate(ctx ActionCtx) error {
 		return err
 	}
 
+	if err = p.resolveSigningKey(ctx); err != nil {
+		return err
+	}
+
 	if err = p.SignerParams.Resolve(ctx); err != nil {
 		return err
 	}
@@ -195,13 +319,324 @@ func (p *AddUserParams) Validate(ctx ActionCtx) error {
 		return err
 	}
 
-	return p.Entity.Valid()
+	if p.src, err = normalizeSourceNetworks(p.src); err != nil {
+		return err
+	}
+	if _, err = normalizeSourceNetworks(p.rmSrc); err != nil {
+		return err
+	}
+
+	if err = loadSubjectsFile(p.denyPubsFile, &p.denyPubs); err != nil {
+		return err
+	}
+	if err = loadSubjectsFile(p.denySubsFile, &p.denySubs); err != nil {
+		return err
+	}
+
+	for _, list := range [][]string{p.allowPubs, p.allowPubsub, p.allowSubs, p.denyPubs, p.denyPubsub, p.denySubs} {
+		if err := validateSubjectMacros(list); err != nil {
+			return err
+		}
+	}
+
+	if p.tagExpiry != "" {
+		if p.tagExpiry, err = reviewTag(p.tagExpiry); err != nil {
+			return err
+		}
+	}
+
+	if err := p.validateBatch(ctx); err != nil {
+		return err
+	}
+	if err := p.Entity.Valid(); err != nil {
+		return err
+	}
+	if err := p.validateCredsDir(ctx); err != nil {
+		return err
+	}
+	if p.credsOut != "" || p.credsDir != "" {
+		if _, err := p.kp.Seed(); err != nil {
+			return errors.New("writing creds requires the user private key - specify a seed or let the key be generated")
+		}
+	}
+
+	p.payload.Number, err = p.payload.NumberValue()
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %s", "payload", p.payload.Value)
+	}
+	if p.payload.Number < -1 {
+		return fmt.Errorf("payload must be -1 (unlimited) or a positive size - got %s", p.payload.Value)
+	}
+
+	return nil
+}
+
+// userCountToken is replaced by the number of the user with --count
+const userCountToken = "{n}"
+
+// validateBatch expands the name of the users added with --count, each
+// user gets its own generated key
+func (p *AddUserParams) validateBatch(ctx ActionCtx) error {
+	if p.count < 1 {
+		return fmt.Errorf("--count must be at least 1 - got %d", p.count)
+	}
+	if !strings.Contains(p.name, userCountToken) {
+		if p.count > 1 {
+			ctx.CurrentCmd().SilenceUsage = false
+			return fmt.Errorf("--count requires %s in the user name", userCountToken)
+		}
+		return nil
+	}
+	if p.keyPath != "" {
+		return errors.New("users added with --count generate their keys - --public-key can't be used")
+	}
+	if p.credsOut != "" {
+		return errors.New("users added with --count can't share an --output-file - use --output-dir")
+	}
+	s := ctx.StoreCtx().Store
+	p.batch = nil
+	for i := 1; i <= p.count; i++ {
+		n := strings.Replace(p.name, userCountToken, strconv.Itoa(i), -1)
+		if s.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(n)) {
+			return fmt.Errorf("the user %q already exists", n)
+		}
+		p.batch = append(p.batch, n)
+	}
+	return nil
+}
+
+// runBatch adds the users expanded from the name, a failure adding a
+// user is reported in its section and doesn't prevent adding the others
+func (p *AddUserParams) runBatch(ctx ActionCtx) (store.Status, error) {
+	r := store.NewDetailedReport(true)
+	for _, n := range p.batch {
+		ur := store.NewReport(store.OK, "user %q", n)
+		r.Add(ur)
+		up := *p
+		up.batch = nil
+		up.Entity = Entity{create: true, kind: nkeys.PrefixByteUser, name: n, editFn: up.editUserClaim}
+		if err := up.Entity.Valid(); err != nil {
+			ur.AddFromError(err)
+			continue
+		}
+		rs, err := up.Run(ctx)
+		if rs != nil {
+			ur.Add(store.HoistChildren(rs)...)
+		}
+		if err != nil {
+			ur.AddFromError(err)
+		}
+	}
+	return r, nil
+}
+
+// validateCredsDir creates the --output-dir if needed and checks that
+// creds can be written into it
+func (p *AddUserParams) validateCredsDir(ctx ActionCtx) error {
+	if p.credsDir == "" {
+		return nil
+	}
+	if p.credsOut != "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("specify only one of --output-file or --output-dir")
+	}
+	dir, err := Expand(p.credsDir)
+	if err != nil {
+		return err
+	}
+	if p.credsDir, err = filepath.Abs(dir); err != nil {
+		return err
+	}
+	if p.dryRun {
+		// a dry run doesn't write, the directory is only checked
+		fi, err := os.Stat(p.credsDir)
+		if os.IsNotExist(err) {
+			p.makeCredsDir = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("%q is not a directory", AbbrevHomePaths(p.credsDir))
+		}
+		if fi.Mode().Perm()&0200 == 0 {
+			return fmt.Errorf("%q is not writable", AbbrevHomePaths(p.credsDir))
+		}
+		return nil
+	}
+	if err := MaybeMakeDir(p.credsDir); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(p.credsDir, ".nsc_creds")
+	if err != nil {
+		return fmt.Errorf("%q is not writable: %v", AbbrevHomePaths(p.credsDir), err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// resolveSigningKey sets the signer to the requested signing key, the key
+// must be one of the account's signing keys
+func (p *AddUserParams) resolveSigningKey(ctx ActionCtx) error {
+	if p.signingKey == "" {
+		return nil
+	}
+	ac, err := ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	kp, err := store.ResolveKey(p.signingKey)
+	if err != nil {
+		return err
+	}
+	if kp == nil {
+		return fmt.Errorf("%q is not a valid signing key", p.signingKey)
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return err
+	}
+	if !ac.SigningKeys.Contains(pk) {
+		if len(ac.SigningKeys) == 0 {
+			return fmt.Errorf("%q is not a signing key - account %q has no signing keys", pk, p.AccountContextParams.Name)
+		}
+		return fmt.Errorf("%q is not a signing key of account %q - valid signing keys are: %s", pk, p.AccountContextParams.Name, strings.Join(ac.SigningKeys, ", "))
+	}
+	if _, err := kp.Seed(); err != nil {
+		// a public key was given, look for the private key in the keystore
+		kp, err = ctx.StoreCtx().KeyStore.GetKeyPair(pk)
+		if err != nil {
+			return err
+		}
+		if kp == nil {
+			return fmt.Errorf("unable to find the private key for signing key %q in the keystore", pk)
+		}
+	}
+	p.signerKP = kp
+	return nil
+}
+
+// validateManifest resolves the account and signer shared by all the users
+// and loads the manifest, the users themselves are validated as they are added
+func (p *AddUserParams) validateManifest(ctx ActionCtx) error {
+	if err := p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	if err := p.validateCredsDir(ctx); err != nil {
+		return err
+	}
+	if err := p.resolveSigningKey(ctx); err != nil {
+		return err
+	}
+	if err := p.SignerParams.Resolve(ctx); err != nil {
+		return err
+	}
+	d, err := Read(p.fromFile)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so a single parser handles both formats
+	if err := yaml.Unmarshal(d, &p.manifest); err != nil {
+		return fmt.Errorf("error parsing manifest %q: %v", p.fromFile, err)
+	}
+	if len(p.manifest) == 0 {
+		return fmt.Errorf("manifest %q doesn't describe any users", p.fromFile)
+	}
+	return nil
+}
+
+// runManifest adds each user in the manifest, a failure adding a user
+// is reported in its section and doesn't prevent adding the others
+func (p *AddUserParams) runManifest(ctx ActionCtx) (store.Status, error) {
+	r := store.NewDetailedReport(true)
+	for _, spec := range p.manifest {
+		ur := store.NewReport(store.OK, "user %q", spec.Name)
+		r.Add(ur)
+		up, err := p.manifestUserParams(ctx, spec)
+		if err != nil {
+			ur.AddFromError(err)
+			continue
+		}
+		rs, err := up.Run(ctx)
+		if rs != nil {
+			ur.Add(store.HoistChildren(rs)...)
+		}
+		if err != nil {
+			ur.AddFromError(err)
+		}
+	}
+	return r, nil
+}
+
+// manifestUserParams returns validated params for adding the user described
+// by the spec, sharing the account and signer of the manifest
+func (p *AddUserParams) manifestUserParams(ctx ActionCtx, spec userSpec) (*AddUserParams, error) {
+	var err error
+	if spec.Name == "" {
+		return nil, errors.New("user name is required")
+	}
+	up := &AddUserParams{
+		AccountContextParams: p.AccountContextParams,
+		SignerParams:         p.SignerParams,
+		credsDir:             p.credsDir,
+		allowPubs:            spec.AllowPub,
+		allowSubs:            spec.AllowSub,
+		allowPubsub:          spec.AllowPubsub,
+		denyPubs:             spec.DenyPub,
+		denySubs:             spec.DenySub,
+		denyPubsub:           spec.DenyPubsub,
+		denyDefault:          spec.DenyDefault,
+		tags:                 spec.Tags,
+	}
+	up.Entity = Entity{create: true, kind: nkeys.PrefixByteUser, name: spec.Name, editFn: up.editUserClaim}
+	up.TimeParams = TimeParams{Start: spec.Start, Expiry: spec.Expiry}
+	if up.TimeParams.Start == "" {
+		up.TimeParams.Start = "0"
+	}
+	if up.TimeParams.Expiry == "" {
+		up.TimeParams.Expiry = "0"
+		if err := up.setDefaultExpiry(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := up.TimeParams.Validate(); err != nil {
+		return nil, err
+	}
+	if up.src, err = normalizeSourceNetworks(spec.SourceNetworks); err != nil {
+		return nil, err
+	}
+	up.payload.Binary = true
+	up.payload.Value = spec.Payload
+	if up.payload.Value == "" {
+		up.payload.Value = "-1"
+	}
+	if up.payload.Number, err = up.payload.NumberValue(); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", "payload", up.payload.Value)
+	}
+	if up.payload.Number < -1 {
+		return nil, fmt.Errorf("payload must be -1 (unlimited) or a positive size - got %s", up.payload.Value)
+	}
+	if err := up.Entity.Valid(); err != nil {
+		return nil, err
+	}
+	return up, nil
 }
 
 func (p *AddUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	var rs store.Status
 	var err error
 
+	if p.fromFile != "" {
+		return p.runManifest(ctx)
+	}
+	if len(p.batch) > 0 {
+		return p.runBatch(ctx)
+	}
+	if p.dryRun {
+		return p.runDryRun(ctx)
+	}
+
 	if err := p.Entity.StoreKeys(p.AccountContextParams.Name); err != nil {
 		return nil, err
 	}
@@ -217,6 +652,14 @@ func (p *AddUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	if rs != nil {
 		r.Add(rs)
 	}
+	for _, t := range p.rmTags {
+		t = strings.ToLower(t)
+		if p.removedTags.Contains(t) {
+			r.AddOK("removed tag %q", t)
+		} else {
+			r.AddWarning("tag %q was not set - nothing to remove", t)
+		}
+	}
 
 	pk, _ := p.kp.PublicKey()
 	if p.generated {
@@ -224,7 +667,27 @@ func (p *AddUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	}
 	// if they gave us a seed, it stored - try to get it
 	ks := ctx.StoreCtx().KeyStore
-	if ks.HasPrivateKey(pk) {
+	if p.credsDir != "" {
+		fp := filepath.Join(p.credsDir, fmt.Sprintf("%s.%s.creds", p.AccountContextParams.Name, p.name))
+		d, err := GenerateConfig(ctx.StoreCtx().Store, p.AccountContextParams.Name, p.name, p.kp)
+		if err != nil {
+			r.AddError("unable to generate creds: %v", err)
+		} else if err := Write(fp, d); err != nil {
+			r.AddError("error writing creds: %v", err)
+		} else {
+			p.credsFilePath = fp
+			r.AddOK("wrote user creds file %q", fp)
+		}
+	} else if p.credsOut != "" {
+		d, err := GenerateConfig(ctx.StoreCtx().Store, p.AccountContextParams.Name, p.name, p.kp)
+		if err != nil {
+			r.AddError("unable to generate creds: %v", err)
+		} else if err := Write(p.credsOut, d); err != nil {
+			r.AddError("error writing creds: %v", err)
+		} else if !IsStdOut(p.credsOut) {
+			r.AddOK("wrote user creds file %q", AbbrevHomePaths(p.credsOut))
+		}
+	} else if ks.HasPrivateKey(pk) {
 		d, err := GenerateConfig(ctx.StoreCtx().Store, p.AccountContextParams.Name, p.name, p.kp)
 		if err != nil {
 			r.AddError("unable to save creds: %v", err)
@@ -245,6 +708,47 @@ func (p *AddUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	return r, nil
 }
 
+// runDryRun prints the claims of the user and reports the files that
+// adding the user would write, nothing is stored
+func (p *AddUserParams) runDryRun(ctx ActionCtx) (store.Status, error) {
+	token, err := p.Entity.EncodeClaim(p.signerKP, ctx)
+	if err != nil {
+		return nil, err
+	}
+	uc, err := jwt.DecodeUserClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	d, err := json.MarshalIndent(uc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := Write("--", append(d, '\n')); err != nil {
+		return nil, err
+	}
+
+	s := ctx.StoreCtx().Store
+	ks := ctx.StoreCtx().KeyStore
+	r := store.NewDetailedReport(true)
+	r.AddOK("dry run - the store and keystore were not changed")
+	r.AddOK("would store the user jwt in %q", AbbrevHomePaths(filepath.Join(s.Dir, store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.name))))
+	if p.generated {
+		r.AddOK("would store the generated user key in %q", AbbrevHomePaths(ks.GetKeyPath(uc.Subject)))
+	}
+	switch {
+	case p.credsDir != "":
+		if p.makeCredsDir {
+			r.AddOK("would create the directory %q", AbbrevHomePaths(p.credsDir))
+		}
+		r.AddOK("would write the user creds to %q", filepath.Join(p.credsDir, fmt.Sprintf("%s.%s.creds", p.AccountContextParams.Name, p.name)))
+	case p.credsOut != "" && !IsStdOut(p.credsOut):
+		r.AddOK("would write the user creds to %q", AbbrevHomePaths(p.credsOut))
+	case p.credsOut == "" && (p.generated || ks.HasPrivateKey(uc.Subject)):
+		r.AddOK("would write the user creds to %q", AbbrevHomePaths(ks.CalcUserCredsPath(p.AccountContextParams.Name, p.name)))
+	}
+	return r, nil
+}
+
 func (p *AddUserParams) editUserClaim(c interface{}, ctx ActionCtx) error {
 	uc, ok := c.(*jwt.UserClaims)
 	if !ok {
@@ -279,20 +783,46 @@ func (p *AddUserParams) editUserClaim(c interface{}, ctx ActionCtx) error {
 	uc.Permissions.Sub.Deny.Add(p.denyPubsub...)
 	sort.Strings(uc.Permissions.Sub.Deny)
 
+	if p.denyDefault {
+		denyByDefault(&uc.Permissions)
+	}
+
+	var srcList jwt.StringList
+	srcList.Add(p.src...)
+	removeSourceNetworks(&srcList, p.rmSrc)
+	sort.Strings(srcList)
+	uc.Src = strings.Join(srcList, ",")
+
+	// user limits are unlimited when not set
+	if p.payload.Number > 0 {
+		uc.Limits.Payload = p.payload.Number
+	}
+
 	uc.Tags.Add(p.tags...)
+	// removals are applied last so they win over tags added in the same command
+	p.removedTags = removeTags(&uc.Tags, p.rmTags)
+	if p.tagExpiry != "" {
+		setReviewTag(&uc.Tags, p.tagExpiry)
+	}
 	sort.Strings(uc.Tags)
 
 	return nil
 }
 
 type ResponsePermsParams struct {
-	respTTL string
-	respMax int
-	rmResp  bool
+	respTTL  string
+	respMax  int
+	respType string
+	rmResp   bool
 }
 
+const (
+	responseTypeSingleton = "singleton"
+	responseTypeStream    = "stream"
+)
+
 func (p *ResponsePermsParams) bindSetFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&p.respTTL, "response-ttl", "", "", "the amount of time the permission is valid (global) - [#ms(millis) | #s(econds) | m(inutes) | h(ours)] - Default is no time limit.")
+	cmd.Flags().StringVarP(&p.respTTL, "response-ttl", "", "", "the amount of time the permission is valid (global) - [#ms(millis) | #s(econds) | m(inutes) | h(ours) | d(ays)] - Default is no time limit.")
 
 	cmd.Flags().IntVarP(&p.respMax, "allow-pub-response", "", 0, "client can publish only to reply subjects [with an optional count] (global)")
 	cmd.Flag("allow-pub-response").NoOptDefVal = "1"
@@ -302,6 +832,10 @@ func (p *ResponsePermsParams) bindSetFlags(cmd *cobra.Command) {
 	cmd.Flag("max-responses").Deprecated = "use --allow-pub-n-responses or --allow-pub-response"
 }
 
+func (p *ResponsePermsParams) bindTypeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&p.respType, "response-type", "", "", "shape of the response permission - singleton (a single response) or stream (--allow-pub-response or unlimited responses)")
+}
+
 func (p *ResponsePermsParams) bindRemoveFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&p.rmResp, "rm-response-perms", "", false, "remove response settings")
 }
@@ -327,7 +861,7 @@ func (p *ResponsePermsParams) parseTTL(s string) (time.Duration, error) {
 	if s == "" {
 		return time.Duration(0), nil
 	}
-	return time.ParseDuration(s)
+	return ParseDuration(s)
 }
 
 func (p *ResponsePermsParams) Edit(hasPerm bool) error {
@@ -361,9 +895,30 @@ func (p *ResponsePermsParams) Edit(hasPerm bool) error {
 	return nil
 }
 
+// Validate checks the response max and ttl together, reporting all
+// problems found in a single error
 func (p *ResponsePermsParams) Validate() error {
-	if err := p.ttlValidator(p.respTTL); err != nil {
-		return err
+	var errs []string
+	if p.respMax < 0 {
+		errs = append(errs, fmt.Sprintf("max responses must be non-negative - got %d", p.respMax))
+	}
+	switch p.respType {
+	case "", responseTypeStream:
+	case responseTypeSingleton:
+		if p.respMax > 1 {
+			errs = append(errs, fmt.Sprintf("singleton responses allow a single message - got max responses %d", p.respMax))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("response type %q is invalid - use singleton or stream", p.respType))
+	}
+	ttl, err := p.parseTTL(p.respTTL)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("response ttl %q is invalid - %v", p.respTTL, err))
+	} else if ttl < 0 {
+		errs = append(errs, fmt.Sprintf("response ttl cannot be negative - got %q", p.respTTL))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid response permissions: %s", strings.Join(errs, ", "))
 	}
 	return nil
 }
@@ -372,15 +927,35 @@ func (p *ResponsePermsParams) Run(uc *jwt.UserClaims, ctx ActionCtx) (*store.Rep
 	r := store.NewDetailedReport(true)
 	if p.rmResp {
 		uc.Resp = nil
-		r.AddOK("removed response permissions")
+		r.AddOK("removed response permissions - the max responses and ttl are gone")
 		return r, nil
 	}
-	if ctx.CurrentCmd().Flag("max-responses").Changed || p.respMax != 0 {
+	cmd := ctx.CurrentCmd()
+	maxChanged := cmd.Flag("allow-pub-response").Changed || cmd.Flag("max-responses").Changed
+	if p.respType != "" {
+		if uc.Resp == nil {
+			uc.Resp = &jwt.ResponsePermission{}
+		}
+		uc.Resp.MaxMsgs = 1
+		if p.respType == responseTypeStream {
+			// a negative max is unlimited
+			uc.Resp.MaxMsgs = -1
+			if p.respMax > 0 {
+				uc.Resp.MaxMsgs = p.respMax
+			}
+		}
+		r.AddOK("set response type to %s with max responses %d", p.respType, uc.Resp.MaxMsgs)
+	} else if maxChanged || p.respMax != 0 {
 		if uc.Resp == nil {
 			uc.Resp = &jwt.ResponsePermission{}
 		}
 		uc.Resp.MaxMsgs = p.respMax
-		r.AddOK("set max responses to %d", p.respMax)
+		if p.respMax == 0 {
+			// unlike --rm-response-perms this keeps the permission and its ttl
+			r.AddOK("set max responses to 0 - kept the response permission, --rm-response-perms removes it")
+		} else {
+			r.AddOK("set max responses to %d", p.respMax)
+		}
 	}
 
 	if p.respTTL != "" {
@@ -388,6 +963,14 @@ func (p *ResponsePermsParams) Run(uc *jwt.UserClaims, ctx ActionCtx) (*store.Rep
 		if err != nil {
 			return nil, err
 		}
+		// a zero ttl clears the ttl but keeps the max responses
+		if v == 0 {
+			if uc.Resp != nil {
+				uc.Resp.Expires = 0
+				r.AddOK("cleared response ttl")
+			}
+			return r, nil
+		}
 		if uc.Resp == nil {
 			uc.Resp = &jwt.ResponsePermission{}
 		}
@@ -396,3 +979,225 @@ func (p *ResponsePermsParams) Run(uc *jwt.UserClaims, ctx ActionCtx) (*store.Rep
 	}
 	return r, nil
 }
+
+// denyByDefault denies all subjects that are not explicitly allowed. The server
+// gives deny precedence over allow, so a ">" deny is only added to a permission
+// that has no allows - a non-empty allow list already rejects everything else.
+func denyByDefault(perms *jwt.Permissions) {
+	for _, p := range []*jwt.Permission{&perms.Pub, &perms.Sub} {
+		if len(p.Allow) == 0 {
+			p.Deny.Add(">")
+			sort.Strings(p.Deny)
+		} else {
+			p.Deny.Remove(">")
+		}
+	}
+}
+
+// normalizeSourceNetworks validates that each entry is a CIDR or an IP,
+// IPs are converted to a CIDR matching only that address
+func normalizeSourceNetworks(src []string) ([]string, error) {
+	var networks []string
+	for _, v := range src {
+		v = strings.TrimSpace(v)
+		if _, _, err := net.ParseCIDR(v); err == nil {
+			networks = append(networks, v)
+			continue
+		}
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, fmt.Errorf("source network %q is not a valid CIDR or IP address", v)
+		}
+		if ip.To4() != nil {
+			networks = append(networks, v+"/32")
+		} else {
+			networks = append(networks, v+"/128")
+		}
+	}
+	return networks, nil
+}
+
+// removeTags removes the tags from the list and returns the ones that
+// were removed, tags are compared in lowercase like the jwt library does
+func removeTags(tags *jwt.TagList, rm []string) jwt.TagList {
+	var removed jwt.TagList
+	for _, t := range rm {
+		t = strings.ToLower(t)
+		if tags.Contains(t) {
+			tags.Remove(t)
+			removed = append(removed, t)
+		}
+	}
+	return removed
+}
+
+// sourceNetworkForms returns the entries that match a source network, an
+// IP matches the IP and the CIDR matching only that address
+func sourceNetworkForms(v string) []string {
+	v = strings.TrimSpace(v)
+	if ip := net.ParseIP(v); ip != nil {
+		if ip.To4() != nil {
+			return []string{v, v + "/32"}
+		}
+		return []string{v, v + "/128"}
+	}
+	if ip, n, err := net.ParseCIDR(v); err == nil {
+		if ones, bits := n.Mask.Size(); ones == bits {
+			return []string{v, ip.String()}
+		}
+	}
+	return []string{v}
+}
+
+// removeSourceNetworks removes the source networks from the list and
+// returns the entries that were removed
+func removeSourceNetworks(list *jwt.StringList, rm []string) []string {
+	var removed []string
+	for _, v := range rm {
+		for _, f := range sourceNetworkForms(v) {
+			if list.Contains(f) {
+				list.Remove(f)
+				removed = append(removed, f)
+			}
+		}
+	}
+	return removed
+}
+
+// readSubjectsFile returns the de-duplicated subjects in the file, one per
+// line - empty lines and lines starting with '#' are ignored
+func readSubjectsFile(fp string) ([]string, error) {
+	d, err := Read(fp)
+	if err != nil {
+		return nil, err
+	}
+	var subjects jwt.StringList
+	for i, v := range strings.Split(string(d), "\n") {
+		v = strings.TrimSpace(v)
+		if v == "" || strings.HasPrefix(v, "#") {
+			continue
+		}
+		var vr jwt.ValidationResults
+		jwt.Subject(v).Validate(&vr)
+		if !vr.IsEmpty() {
+			return nil, fmt.Errorf("%s line %d: %s", fp, i+1, vr.Issues[0].Description)
+		}
+		subjects.Add(v)
+	}
+	return subjects, nil
+}
+
+// loadSubjectsFile appends the subjects in the file to the list, an
+// empty path is ignored
+func loadSubjectsFile(fp string, list *[]string) error {
+	if fp == "" {
+		return nil
+	}
+	subjects, err := readSubjectsFile(fp)
+	if err != nil {
+		return err
+	}
+	*list = append(*list, subjects...)
+	return nil
+}
+
+// subjectMacros are the permission macros the server expands when the
+// user connects, the value is true if the macro takes an argument
+var subjectMacros = map[string]bool{
+	"name":         false,
+	"subject":      false,
+	"account-name": false,
+	"tag":          true,
+	"account-tag":  true,
+}
+
+// validateSubjectMacros checks that the {{macro}} references in the subjects
+// are balanced and name a macro known to the server
+func validateSubjectMacros(subjects []string) error {
+	for _, v := range subjects {
+		if err := validateSubjectMacro(v); err != nil {
+			return fmt.Errorf("subject %q: %v", v, err)
+		}
+	}
+	return nil
+}
+
+func validateSubjectMacro(subject string) error {
+	s := subject
+	for {
+		start := strings.Index(s, "{{")
+		end := strings.Index(s, "}}")
+		if start == -1 && end == -1 {
+			return nil
+		}
+		if start == -1 || (end != -1 && end < start) {
+			return errors.New("'}}' without a matching '{{'")
+		}
+		if end == -1 {
+			return errors.New("'{{' without a matching '}}'")
+		}
+		macro := strings.TrimSpace(s[start+2 : end])
+		if strings.Contains(macro, "{{") {
+			return errors.New("'{{' without a matching '}}'")
+		}
+		name, arg := macro, ""
+		hasArgs := false
+		if i := strings.Index(macro, "("); i != -1 {
+			if !strings.HasSuffix(macro, ")") {
+				return fmt.Errorf("macro %q is missing a closing ')'", macro)
+			}
+			name = strings.TrimSpace(macro[:i])
+			arg = strings.TrimSpace(macro[i+1 : len(macro)-1])
+			hasArgs = true
+		}
+		takesArg, ok := subjectMacros[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown macro %q", macro)
+		}
+		if takesArg && arg == "" {
+			return fmt.Errorf("macro %q requires an argument - {{%s(<name>)}}", macro, name)
+		}
+		if !takesArg && hasArgs && arg != "" {
+			return fmt.Errorf("macro %q doesn't take an argument", macro)
+		}
+		s = s[end+2:]
+	}
+}
+
+const reviewTagPrefix = "review:"
+
+// reviewTag returns the normalized review tag for a yyyy-mm-dd date
+func reviewTag(date string) (string, error) {
+	t, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return "", fmt.Errorf("review date %q is invalid - expected yyyy-mm-dd", date)
+	}
+	return reviewTagPrefix + t.Format("2006-01-02"), nil
+}
+
+// setReviewTag replaces any review tag with the specified one
+func setReviewTag(tags *jwt.TagList, tag string) {
+	// Remove shifts the list, so the matches are collected first
+	var old []string
+	for _, t := range *tags {
+		if strings.HasPrefix(t, reviewTagPrefix) {
+			old = append(old, t)
+		}
+	}
+	tags.Remove(old...)
+	tags.Add(tag)
+	sort.Strings(*tags)
+}
+
+// reviewDate returns the date in the review tag if there's one
+func reviewDate(tags jwt.TagList) (time.Time, bool) {
+	for _, t := range tags {
+		if strings.HasPrefix(t, reviewTagPrefix) {
+			d, err := time.Parse("2006-01-02", strings.TrimPrefix(t, reviewTagPrefix))
+			if err == nil {
+				return d, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -16,9 +16,16 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
 	"github.com/stretchr/testify/require"
 )
 
@@ -74,7 +81,7 @@ func Test_AddUserInteractive(t *testing.T) {
 	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "--name", "A")
 	require.NoError(t, err, "account creation")
 
-	inputs := []interface{}{"U", true, "2018-01-01", "2050-01-01", 0}
+	inputs := []interface{}{"U", true, "2018-01-01", "2050-01-01", "-1", 0}
 
 	cmd := CreateAddUserCmd()
 	HoistRootFlags(cmd)
@@ -87,6 +94,22 @@ func Test_AddUserInteractive(t *testing.T) {
 	require.Nil(t, up.Resp)
 }
 
+func Test_AddUserInteractivePayload(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	inputs := []interface{}{"U", true, "0", "0", "1Mb", 0}
+	cmd := CreateAddUserCmd()
+	HoistRootFlags(cmd)
+	_, _, err := ExecuteInteractiveCmd(cmd, inputs)
+	require.NoError(t, err)
+
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, int64(1048576), uc.Limits.Payload)
+}
+
 func validateAddUserClaims(t *testing.T, ts *TestStore) {
 	skp := ts.GetUserKey(t, "A", "U")
 	_, err := skp.Seed()
@@ -180,7 +203,7 @@ func Test_AddUser_InteractiveResp(t *testing.T) {
 	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "--name", "A")
 	require.NoError(t, err, "account creation")
 
-	inputs := []interface{}{"U", true, true, "100", "1000ms", "2018-01-01", "2050-01-01", 0}
+	inputs := []interface{}{"U", true, true, "100", "1000ms", "2018-01-01", "2050-01-01", "-1", 0}
 	cmd := CreateAddUserCmd()
 	HoistRootFlags(cmd)
 	_, _, err = ExecuteInteractiveCmd(cmd, inputs)
@@ -238,3 +261,460 @@ func Test_AddUserWithResponsePerms2(t *testing.T) {
 	d, _ := time.ParseDuration("2ms")
 	require.Equal(t, d, uc.Resp.Expires)
 }
+
+func Test_AddUserDenyDefault(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--deny-default", "--allow-pub", "foo")
+	require.NoError(t, err)
+
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	// only foo can be published - a deny on > would override the allow
+	require.ElementsMatch(t, uc.Pub.Allow, []string{"foo"})
+	require.Empty(t, uc.Pub.Deny)
+	// nothing is allowed on sub, so everything is denied
+	require.Empty(t, uc.Sub.Allow)
+	require.ElementsMatch(t, uc.Sub.Deny, []string{">"})
+}
+
+func Test_AddUserPayload(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--payload", "1Mb")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, int64(1048576), uc.Limits.Payload)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "UU", "--payload", "-1")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "UU")
+	require.NoError(t, err)
+	require.Zero(t, uc.Limits.Payload)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "UUU", "--payload", "-5")
+	require.Error(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "UUU", "--payload", "lots")
+	require.Error(t, err)
+}
+
+func Test_AddUserRemoveTag(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, stderr, err := ExecuteCmd(CreateAddUserCmd(), "U", "--tag", "foo,bar", "--rm-tag", "FOO")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `removed tag "foo"`)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Tags, []string{"bar"})
+
+	_, stderr, err = ExecuteCmd(CreateAddUserCmd(), "V", "--tag", "foo", "--rm-tag", "baz")
+	require.NoError(t, err)
+	require.NotContains(t, stderr, `removed tag "baz"`)
+	require.Contains(t, stderr, `tag "baz" was not set - nothing to remove`)
+	uc, err = ts.Store.ReadUserClaim("A", "V")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Tags, []string{"foo"})
+}
+
+func Test_SetReviewTagReplacesAll(t *testing.T) {
+	tags := jwt.TagList{"a", "review:2020-01-01", "review:2021-01-01", "z"}
+	setReviewTag(&tags, "review:2022-01-01")
+	require.Equal(t, jwt.TagList{"a", "review:2022-01-01", "z"}, tags)
+}
+
+func Test_AddUserCredsStdout(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	stdout, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--output-file", "--")
+	require.NoError(t, err)
+	require.Contains(t, stdout, "-----BEGIN NATS USER JWT-----")
+	require.Contains(t, stdout, "-----BEGIN USER NKEY SEED-----")
+	_, err = os.Stat(ts.KeyStore.CalcUserCredsPath("A", "U"))
+	require.True(t, os.IsNotExist(err))
+
+	_, pk, _ := CreateUserKey(t)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--public-key", pk, "--output-file", "--")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires the user private key")
+}
+
+func Test_AddUserOutputDir(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	dir := filepath.Join(ts.Dir, "app", "config")
+	_, stderr, err := ExecuteCmd(CreateAddUserCmd(), "U", "--output-dir", dir)
+	require.NoError(t, err)
+	fp := filepath.Join(dir, "A.U.creds")
+	require.FileExists(t, fp)
+	require.Contains(t, stderr, fmt.Sprintf("wrote user creds file %q", fp))
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	require.Contains(t, string(d), "-----BEGIN USER NKEY SEED-----")
+	_, err = os.Stat(ts.KeyStore.CalcUserCredsPath("A", "U"))
+	require.True(t, os.IsNotExist(err))
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--output-dir", dir, "--output-file", "--")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify only one of --output-file or --output-dir")
+
+	nf := filepath.Join(ts.Dir, "file")
+	require.NoError(t, ioutil.WriteFile(nf, []byte("x"), 0600))
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--output-dir", nf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "it is not a dir")
+}
+
+func Test_AddUserPrivateKeyFlags(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+	kf := ts.GetAccountKeyPath(t, "A")
+	seed, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "U", "--private-key-file", kf)
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, apk, uc.Issuer)
+	// the key file is read directly, not passed on as a --private-key path
+	// binding the flags resets them to their defaults
+	kc := HoistRootFlags(CreateAddUserCmd())
+	KeyFileFlag = kf
+	require.NoError(t, resolvePrivateKeyFlags(kc))
+	require.Empty(t, KeyPathFlag)
+	require.NotNil(t, keyFileKP)
+	pk, err := keyFileKP.PublicKey()
+	require.NoError(t, err)
+	require.Equal(t, apk, pk)
+	ResetSharedFlags()
+
+	_, stderr, err := ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "V", "--private-key", seed)
+	require.NoError(t, err)
+	require.NotContains(t, stderr, "deprecated")
+	uc, err = ts.Store.ReadUserClaim("A", "V")
+	require.NoError(t, err)
+	require.Equal(t, apk, uc.Issuer)
+
+	// a path in --private-key still works but is deprecated
+	_, stderr, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "W", "--private-key", kf)
+	require.NoError(t, err)
+	require.Contains(t, stderr, "--private-key with a path is deprecated")
+
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "X", "--private-key", seed, "--private-key-file", kf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify only one of --private-key or --private-key-file")
+
+	// the file flag never treats its value as a key
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "X", "--private-key-file", seed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no such file or directory")
+	require.False(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("X")))
+
+	oc, err := ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "X", "--private-key-file", ts.KeyStore.GetKeyPath(oc.Subject))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not an account key")
+	require.False(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("X")))
+}
+
+func Test_AddUserDryRun(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	keys, err := ts.KeyStore.AllKeys()
+	require.NoError(t, err)
+
+	stdout, stderr, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub", "foo.>", "--deny-sub", "bar", "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "dry run - the store and keystore were not changed")
+	require.Contains(t, stderr, "would store the generated user key")
+	require.Contains(t, stderr, "would write the user creds to")
+
+	var uc jwt.UserClaims
+	require.NoError(t, json.Unmarshal([]byte(stdout), &uc))
+	require.Equal(t, "U", uc.Name)
+	require.True(t, uc.Pub.Allow.Contains("foo.>"))
+	require.True(t, uc.Sub.Deny.Contains("bar"))
+
+	require.False(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("U")))
+	require.False(t, ts.KeyStore.HasPrivateKey(uc.Subject))
+	after, err := ts.KeyStore.AllKeys()
+	require.NoError(t, err)
+	require.ElementsMatch(t, keys, after)
+	_, err = os.Stat(ts.KeyStore.CalcUserCredsPath("A", "U"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func Test_AddUserDryRunOutputDir(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	// a missing directory isn't created
+	dir := filepath.Join(ts.Dir, "creds")
+	_, stderr, err := ExecuteCmd(CreateAddUserCmd(), "U", "--output-dir", dir, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "would create the directory")
+	require.Contains(t, stderr, filepath.Join(dir, "A.U.creds"))
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+
+	// an existing directory isn't written to
+	require.NoError(t, os.Mkdir(dir, 0700))
+	_, stderr, err = ExecuteCmd(CreateAddUserCmd(), "U", "--output-dir", dir, "--dry-run")
+	require.NoError(t, err)
+	require.NotContains(t, stderr, "would create the directory")
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, infos)
+
+	require.NoError(t, os.Chmod(dir, 0500))
+	defer os.Chmod(dir, 0700)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U", "--output-dir", dir, "--dry-run")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not writable")
+}
+
+func Test_AddUserSubjectMacros(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "--name", "U", "--allow-sub", "_INBOX.{{account-name}}.>",
+		"--allow-pub", "{{tag(team)}}.{{name()}}.>")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.True(t, uc.Sub.Allow.Contains("_INBOX.{{account-name}}.>"))
+	require.True(t, uc.Pub.Allow.Contains("{{tag(team)}}.{{name()}}.>"))
+
+	tests := []struct {
+		subject string
+		message string
+	}{
+		{"a.{{unknown}}.>", `unknown macro "unknown"`},
+		{"a.{{account-name}.>", "'{{' without a matching '}}'"},
+		{"a.account-name}}.>", "'}}' without a matching '{{'"},
+		{"a.{{tag()}}", "requires an argument"},
+		{"a.{{name(x)}}", "doesn't take an argument"},
+	}
+	for i, tc := range tests {
+		_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", fmt.Sprintf("V%d", i), "--deny-sub", tc.subject)
+		require.Error(t, err, tc.subject)
+		require.Contains(t, err.Error(), tc.message, tc.subject)
+	}
+}
+
+func Test_AddUserCount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, stderr, err := ExecuteCmd(CreateAddUserCmd(), "--name", "worker-{n}", "--count", "5", "--allow-pub", "work.>")
+	require.NoError(t, err)
+	keys := make(map[string]bool)
+	for i := 1; i <= 5; i++ {
+		n := fmt.Sprintf("worker-%d", i)
+		require.Contains(t, stderr, fmt.Sprintf("user %q", n))
+		uc, err := ts.Store.ReadUserClaim("A", n)
+		require.NoError(t, err)
+		require.Equal(t, n, uc.Name)
+		require.True(t, uc.Pub.Allow.Contains("work.>"))
+		require.False(t, keys[uc.Subject])
+		keys[uc.Subject] = true
+		require.True(t, ts.KeyStore.HasPrivateKey(uc.Subject))
+		require.FileExists(t, ts.KeyStore.CalcUserCredsPath("A", n))
+	}
+	require.Len(t, keys, 5)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "worker", "--count", "2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--count requires {n} in the user name")
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "worker-{n}", "--count", "6")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `the user "worker-1" already exists`)
+	require.False(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("worker-6")))
+}
+
+func Test_AddUserRelativeExpiry(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--expiry", "30d")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.InDelta(t, time.Now().AddDate(0, 0, 30).Unix(), uc.Expires, 60)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--expiry", "30x")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `expiry "30x" is invalid`)
+}
+
+func Test_AddUserValidFor(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--valid-for", "24h")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.InDelta(t, uc.IssuedAt+24*60*60, uc.Expires, 5)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--valid-for", "24h", "--expiry", "1d")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify only one of --valid-for or --expiry")
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--valid-for", "2050-01-01")
+	require.Error(t, err)
+}
+
+func Test_AddUserSourceNetwork(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--source-network", "10.0.0.0/24,2001:db8::/32,192.168.1.1")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/24,192.168.1.1/32,2001:db8::/32", uc.Src)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--source-network", "10.0.0.0/24,10.1.0.0/16", "--rm-source-network", "10.1.0.0/16")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "V")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/24", uc.Src)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "W", "--source-network", "10.0.0/24")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"10.0.0/24"`)
+}
+
+func Test_AddUserFromFile(t *testing.T) {
+	ts := NewTestStore(t, "add_user")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	manifest := `
+- name: svc
+  allow-pub: [orders.new]
+  allow-sub: [orders.>]
+  tags: [service]
+  payload: 1K
+- name: bad
+  expiry: yesterday
+- name: ops
+  deny-pubsub: [admin.>]
+  expiry: 30d
+`
+	fp := filepath.Join(ts.Dir, "users.yaml")
+	require.NoError(t, ioutil.WriteFile(fp, []byte(manifest), 0600))
+
+	_, stderr, err := ExecuteCmd(CreateAddUserCmd(), "--from-file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1 job failed")
+	require.Contains(t, stderr, `user "bad"`)
+	require.Contains(t, stderr, `expiry "yesterday" is invalid`)
+
+	uc, err := ts.Store.ReadUserClaim("A", "svc")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"orders.new"}, uc.Pub.Allow)
+	require.ElementsMatch(t, []string{"orders.>"}, uc.Sub.Allow)
+	require.ElementsMatch(t, []string{"service"}, uc.Tags)
+	require.Equal(t, int64(1024), uc.Limits.Payload)
+
+	uc, err = ts.Store.ReadUserClaim("A", "ops")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"admin.>"}, uc.Pub.Deny)
+	require.ElementsMatch(t, []string{"admin.>"}, uc.Sub.Deny)
+	require.NotZero(t, uc.Expires)
+
+	require.False(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("bad")))
+	require.NotEmpty(t, ts.KeyStore.GetUserCredsPath("A", "svc"))
+}
+
+func Test_AddUserFromFileJSON(t *testing.T) {
+	ts := NewTestStore(t, "add_user")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	fp := filepath.Join(ts.Dir, "users.json")
+	require.NoError(t, ioutil.WriteFile(fp, []byte(`[{"name": "u", "allow-pub": ["a"]}]`), 0600))
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "--from-file", fp)
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "u")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"a"}, uc.Pub.Allow)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--from-file", fp, "--name", "x")
+	require.Error(t, err)
+}
+
+func Test_AddUserSigningKey(t *testing.T) {
+	ts := NewTestStore(t, "add_user")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	seed1, pk1, _ := CreateAccountKey(t)
+	_, pk2, kp2 := CreateAccountKey(t)
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", pk1, "--sk", pk2)
+	require.NoError(t, err)
+	// the private key of the second signing key is in the keystore
+	_, err = ts.KeyStore.Store(kp2)
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U1", "--signing-key", string(seed1))
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U1")
+	require.NoError(t, err)
+	require.Equal(t, pk1, uc.Issuer)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), uc.IssuerAccount)
+
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U2", "--signing-key", pk2)
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U2")
+	require.NoError(t, err)
+	require.Equal(t, pk2, uc.Issuer)
+
+	_, pk3, _ := CreateAccountKey(t)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U3", "--signing-key", pk3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "valid signing keys are")
+	require.Contains(t, err.Error(), pk1)
+	require.Contains(t, err.Error(), pk2)
+}
+
+func Test_AddUserDenyFromFile(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	fp := filepath.Join(ts.Dir, "deny.txt")
+	require.NoError(t, ioutil.WriteFile(fp, []byte("a.b\nc.>\n"), 0600))
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--sub-deny-from-file", fp, "--deny-sub", "d")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Permissions.Sub.Deny, []string{"a.b", "c.>", "d"})
+	require.Empty(t, uc.Permissions.Pub.Deny)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createCheckDuplicateKeysCmd() *cobra.Command {
+	var operator string
+	cmd := &cobra.Command{
+		Use:          "duplicate-keys",
+		Short:        "Report public keys used by more than one account or user",
+		Example:      "nsc check duplicate-keys --operator O",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return errors.New("no store set - `env --store <dir>`")
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return errors.New("no operator set - `env --operator <name>`")
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			dups, unreadable, err := findDuplicateKeys(s)
+			if err != nil {
+				return err
+			}
+			cmd.Println(renderDuplicateKeys(config.Operator, dups))
+			if len(unreadable) > 0 {
+				cmd.Println(renderUnreadableEntries(unreadable))
+			}
+			switch {
+			case len(dups) > 0 && len(unreadable) > 0:
+				return fmt.Errorf("found %d public keys used by more than one entity and %d unreadable entries", len(dups), len(unreadable))
+			case len(dups) > 0:
+				return fmt.Errorf("found %d public keys used by more than one entity", len(dups))
+			case len(unreadable) > 0:
+				return fmt.Errorf("unable to read %d entries - their keys were not checked", len(unreadable))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	return cmd
+}
+
+func init() {
+	checkCmd.AddCommand(createCheckDuplicateKeysCmd())
+}
+
+type duplicateKey struct {
+	key      string
+	entities []string
+}
+
+type unreadableEntry struct {
+	entity string
+	err    error
+}
+
+// findDuplicateKeys returns the public keys that are the subject of more than
+// one account or user in the store sorted by key. Claims that can't be read
+// are returned as unreadable and the rest of the store is still checked.
+func findDuplicateKeys(s *store.Store) ([]duplicateKey, []unreadableEntry, error) {
+	var unreadable []unreadableEntry
+	owners := make(map[string][]string)
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(accounts)
+	for _, an := range accounts {
+		ac, err := s.ReadAccountClaim(an)
+		if err != nil {
+			unreadable = append(unreadable, unreadableEntry{entity: fmt.Sprintf("account %s", an), err: err})
+		} else {
+			owners[ac.Subject] = append(owners[ac.Subject], fmt.Sprintf("account %s", an))
+		}
+
+		users, err := s.ListEntries(store.Accounts, an, store.Users)
+		if err != nil {
+			unreadable = append(unreadable, unreadableEntry{entity: fmt.Sprintf("users of account %s", an), err: err})
+			continue
+		}
+		sort.Strings(users)
+		for _, un := range users {
+			uc, err := s.ReadUserClaim(an, un)
+			if err != nil {
+				unreadable = append(unreadable, unreadableEntry{entity: fmt.Sprintf("user %s/%s", an, un), err: err})
+				continue
+			}
+			owners[uc.Subject] = append(owners[uc.Subject], fmt.Sprintf("user %s/%s", an, un))
+		}
+	}
+
+	var dups []duplicateKey
+	for k, v := range owners {
+		if len(v) > 1 {
+			dups = append(dups, duplicateKey{key: k, entities: v})
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		return dups[i].key < dups[j].key
+	})
+	return dups, unreadable, nil
+}
+
+func renderDuplicateKeys(operator string, dups []duplicateKey) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle(fmt.Sprintf("Duplicate Keys for Operator %q", operator))
+	if len(dups) == 0 {
+		table.AddRow("No duplicate keys")
+		return table.Render()
+	}
+	table.AddHeaders("Public Key", "Used By")
+	for _, d := range dups {
+		table.AddRow(d.key, strings.Join(d.entities, ", "))
+	}
+	return table.Render()
+}
+
+func renderUnreadableEntries(entries []unreadableEntry) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Unreadable Entries")
+	table.AddHeaders("Entry", "Error")
+	for _, e := range entries {
+		table.AddRow(e.entity, e.err.Error())
+	}
+	return table.Render()
+}
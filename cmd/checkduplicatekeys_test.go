@@ -0,0 +1,85 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckDuplicateKeys(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	ts.AddUser(t, "A", "V")
+
+	_, stderr, err := ExecuteCmd(createCheckDuplicateKeysCmd())
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No duplicate keys")
+
+	pk := ts.GetUserPublicKey(t, "A", "U")
+	// store a second user claim for the same key
+	uc := jwt.NewUserClaims(pk)
+	uc.Name = "W"
+	token, err := uc.Encode(ts.GetAccountKey(t, "A"))
+	require.NoError(t, err)
+	_, err = ts.Store.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	_, stderr, err = ExecuteCmd(createCheckDuplicateKeysCmd(), "--operator", "O")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 1 public keys used by more than one entity")
+	stderr = StripTableDecorations(stderr)
+	require.Contains(t, stderr, pk)
+	require.Contains(t, stderr, "user A/U, user A/W")
+	require.NotContains(t, stderr, ts.GetUserPublicKey(t, "A", "V"))
+}
+
+func Test_CheckDuplicateKeysUnreadableEntry(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	require.NoError(t, ts.Store.Write([]byte("not a jwt"), store.Accounts, "A", store.Users, store.JwtName("X")))
+
+	_, stderr, err := ExecuteCmd(createCheckDuplicateKeysCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to read 1 entries")
+	stderr = StripTableDecorations(stderr)
+	require.Contains(t, stderr, "No duplicate keys")
+	require.Contains(t, stderr, "user A/X")
+
+	// the scan continues past the unreadable user
+	pk := ts.GetUserPublicKey(t, "A", "U")
+	uc := jwt.NewUserClaims(pk)
+	uc.Name = "W"
+	token, err := uc.Encode(ts.GetAccountKey(t, "A"))
+	require.NoError(t, err)
+	_, err = ts.Store.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	_, stderr, err = ExecuteCmd(createCheckDuplicateKeysCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 1 public keys used by more than one entity and 1 unreadable entries")
+	stderr = StripTableDecorations(stderr)
+	require.Contains(t, stderr, "user A/U, user A/W")
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createCheckExpiryConsistencyCmd() *cobra.Command {
+	var operator string
+	cmd := &cobra.Command{
+		Use:          "expiry-consistency",
+		Short:        "Report accounts that outlive the operator and users that outlive their account",
+		Example:      "nsc check expiry-consistency",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return errors.New("no store set - `env --store <dir>`")
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return errors.New("no operator set - `env --operator <name>`")
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			issues, err := findExpiryInconsistencies(s)
+			if err != nil {
+				return err
+			}
+			cmd.Println(renderExpiryInconsistencies(issues))
+			if len(issues) > 0 {
+				return fmt.Errorf("found %d claims that outlive their issuer", len(issues))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	return cmd
+}
+
+func init() {
+	checkCmd.AddCommand(createCheckExpiryConsistencyCmd())
+}
+
+type expiryInconsistency struct {
+	kind          string
+	name          string
+	expires       int64
+	parent        string
+	parentExpires int64
+}
+
+// outlives returns true if a claim expiring at expires stays valid past
+// the expiry of its parent, zero meaning the claim doesn't expire
+func outlives(expires int64, parentExpires int64) bool {
+	if parentExpires == 0 {
+		return false
+	}
+	return expires == 0 || expires > parentExpires
+}
+
+// findExpiryInconsistencies walks the operator, account and user chains
+// and returns the claims that expire after their parent
+func findExpiryInconsistencies(s *store.Store) ([]expiryInconsistency, error) {
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(accounts)
+	var issues []expiryInconsistency
+	for _, an := range accounts {
+		ac, err := s.ReadAccountClaim(an)
+		if err != nil {
+			return nil, err
+		}
+		if outlives(ac.Expires, oc.Expires) {
+			issues = append(issues, expiryInconsistency{kind: "account", name: an, expires: ac.Expires,
+				parent: s.Info.Name, parentExpires: oc.Expires})
+		}
+		users, err := s.ListEntries(store.Accounts, an, store.Users)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(users)
+		for _, un := range users {
+			uc, err := s.ReadUserClaim(an, un)
+			if err != nil {
+				return nil, err
+			}
+			if outlives(uc.Expires, ac.Expires) {
+				issues = append(issues, expiryInconsistency{kind: "user", name: fmt.Sprintf("%s/%s", an, un),
+					expires: uc.Expires, parent: an, parentExpires: ac.Expires})
+			}
+		}
+	}
+	return issues, nil
+}
+
+func renderExpiryInconsistencies(issues []expiryInconsistency) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Expiry Consistency")
+	if len(issues) == 0 {
+		table.AddRow("No claims outlive their issuer")
+		return table.Render()
+	}
+	table.AddHeaders("Kind", "Name", "Expires", "Issuer", "Issuer Expires")
+	for _, i := range issues {
+		expires := "Never"
+		if i.expires > 0 {
+			expires = RenderDate(i.expires)
+		}
+		table.AddRow(i.kind, i.name, expires, i.parent, RenderDate(i.parentExpires))
+	}
+	return table.Render()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckExpiryConsistencyNone(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, stderr, err := ExecuteCmd(createCheckExpiryConsistencyCmd())
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No claims outlive their issuer")
+}
+
+func Test_CheckExpiryConsistencyUserOutlivesAccount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "--expiry", "30d")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "short", "--expiry", "10d")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "long", "--expiry", "60d")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "forever")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createCheckExpiryConsistencyCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 2 claims that outlive their issuer")
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, "user A/long")
+	require.Contains(t, out, "user A/forever Never A")
+	require.NotContains(t, out, "A/short")
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createCheckOrphanUsersCmd() *cobra.Command {
+	var operator string
+	var account string
+	var all bool
+	cmd := &cobra.Command{
+		Use:          "orphan-users",
+		Short:        "Report users not issued by their account or one of its signing keys",
+		Example:      "nsc check orphan-users --account A\nnsc check orphan-users --all",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && account != "" {
+				return errors.New("specify only one of --account or --all")
+			}
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return errors.New("no store set - `env --store <dir>`")
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return errors.New("no operator set - `env --operator <name>`")
+			}
+			if account != "" {
+				if err := config.SetAccount(account); err != nil {
+					return err
+				}
+			}
+			accounts := []string{config.Account}
+			if all {
+				var err error
+				if accounts, err = config.ListAccounts(); err != nil {
+					return err
+				}
+				sort.Strings(accounts)
+			} else if config.Account == "" {
+				return errors.New("no account set - `env --account <name>` or specify --all")
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			var orphans []orphanUser
+			for _, a := range accounts {
+				o, err := findOrphanUsers(s, a)
+				if err != nil {
+					return err
+				}
+				orphans = append(orphans, o...)
+			}
+			cmd.Println(renderOrphanUsers(orphans))
+			if len(orphans) > 0 {
+				return fmt.Errorf("found %d users not issued by their account", len(orphans))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().StringVarP(&account, "account", "a", "", "account name")
+	cmd.Flags().BoolVarP(&all, "all", "", false, "check the users of all accounts")
+	return cmd
+}
+
+func init() {
+	checkCmd.AddCommand(createCheckOrphanUsersCmd())
+}
+
+type orphanUser struct {
+	account string
+	user    string
+	issuer  string
+	reason  string
+}
+
+// findOrphanUsers returns the users of the account that are not issued by
+// the account's identity or one of its signing keys
+func findOrphanUsers(s *store.Store, account string) ([]orphanUser, error) {
+	ac, err := s.ReadAccountClaim(account)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.ListEntries(store.Accounts, account, store.Users)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(users)
+	var orphans []orphanUser
+	for _, un := range users {
+		uc, err := s.ReadUserClaim(account, un)
+		if err != nil {
+			return nil, err
+		}
+		if reason := orphanReason(ac, uc); reason != "" {
+			orphans = append(orphans, orphanUser{account: account, user: un, issuer: uc.Issuer, reason: reason})
+		}
+	}
+	return orphans, nil
+}
+
+// orphanReason returns why the user doesn't resolve to the account, or an
+// empty string if the user was issued by the account
+func orphanReason(ac *jwt.AccountClaims, uc *jwt.UserClaims) string {
+	if uc.Issuer == ac.Subject {
+		if uc.IssuerAccount != "" && uc.IssuerAccount != ac.Subject {
+			return "issuer account is not the account"
+		}
+		return ""
+	}
+	if !ac.SigningKeys.Contains(uc.Issuer) {
+		if uc.IssuerAccount == ac.Subject {
+			return "issued by a signing key not in the account"
+		}
+		return "issuer is not the account or one of its signing keys"
+	}
+	if uc.IssuerAccount != ac.Subject {
+		return "issued by a signing key without the issuer account set"
+	}
+	return ""
+}
+
+func renderOrphanUsers(orphans []orphanUser) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Orphan Users")
+	if len(orphans) == 0 {
+		table.AddRow("No orphan users")
+		return table.Render()
+	}
+	table.AddHeaders("Account", "User", "Issuer", "Reason")
+	for _, o := range orphans {
+		table.AddRow(o.account, o.user, o.issuer, o.reason)
+	}
+	return table.Render()
+}
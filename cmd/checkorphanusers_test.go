@@ -0,0 +1,50 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckOrphanUsers(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	seed, pk, _ := CreateAccountKey(t)
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", pk)
+	require.NoError(t, err)
+	ts.AddUser(t, "A", "good")
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "sk", "--signing-key", string(seed))
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createCheckOrphanUsersCmd(), "--account", "A")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No orphan users")
+
+	// removing the signing key orphans the user it issued
+	_, _, err = ExecuteCmd(createEditAccount(), "--rm-sk", pk)
+	require.NoError(t, err)
+
+	_, stderr, err = ExecuteCmd(createCheckOrphanUsersCmd(), "--all")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 1 users not issued by their account")
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, "A sk "+pk+" issued by a signing key not in the account")
+	require.NotContains(t, out, "good")
+}
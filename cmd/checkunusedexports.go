@@ -0,0 +1,136 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createCheckUnusedExportsCmd() *cobra.Command {
+	var operator string
+	cmd := &cobra.Command{
+		Use:          "unused-exports",
+		Short:        "List exports that are not imported by any other account",
+		Example:      "nsc check unused-exports --operator O",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return errors.New("no store set - `env --store <dir>`")
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return errors.New("no operator set - `env --operator <name>`")
+			}
+			names, err := config.ListAccounts()
+			if err != nil {
+				return err
+			}
+			sort.Strings(names)
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			var accounts []*jwt.AccountClaims
+			for _, n := range names {
+				ac, err := s.ReadAccountClaim(n)
+				if err != nil {
+					return err
+				}
+				accounts = append(accounts, ac)
+			}
+			cmd.Println(renderUnusedExports(config.Operator, findUnusedExports(accounts)))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	return cmd
+}
+
+func init() {
+	checkCmd.AddCommand(createCheckUnusedExportsCmd())
+}
+
+type unusedExport struct {
+	account *jwt.AccountClaims
+	export  *jwt.Export
+}
+
+// findUnusedExports returns the exports for which no import in another account
+// references the exporting account and a subject contained in the export
+func findUnusedExports(accounts []*jwt.AccountClaims) []unusedExport {
+	var unused []unusedExport
+	for _, ac := range accounts {
+		for _, e := range ac.Exports {
+			if !isExportImported(ac.Subject, e, accounts) {
+				unused = append(unused, unusedExport{account: ac, export: e})
+			}
+		}
+	}
+	return unused
+}
+
+func isExportImported(exporter string, e *jwt.Export, accounts []*jwt.AccountClaims) bool {
+	for _, ac := range accounts {
+		if ac.Subject == exporter {
+			continue
+		}
+		for _, im := range ac.Imports {
+			if im.Account != exporter || im.Type != e.Type {
+				continue
+			}
+			if importRemoteSubject(im).IsContainedIn(e.Subject) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importRemoteSubject returns the subject of the import as seen by the exporter
+func importRemoteSubject(im *jwt.Import) jwt.Subject {
+	// service imports store the remote subject in To
+	if im.IsService() && im.To != "" {
+		return im.To
+	}
+	return im.Subject
+}
+
+func renderUnusedExports(operator string, unused []unusedExport) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle(fmt.Sprintf("Unused Exports for Operator %q", operator))
+	if len(unused) == 0 {
+		table.AddRow("No unused exports")
+		return table.Render()
+	}
+	table.AddHeaders("Account", "Name", "Type", "Subject")
+	for _, u := range unused {
+		table.AddRow(u.account.Name, u.export.Name, u.export.Type.String(), string(u.export.Subject))
+	}
+	return table.Render()
+}
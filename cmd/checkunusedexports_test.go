@@ -0,0 +1,55 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckUnusedExports(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	ts.AddExport(t, "A", jwt.Service, "q", false)
+	ts.AddExport(t, "A", jwt.Stream, "orphan.>", false)
+	ts.AddAccount(t, "B")
+	ts.AddImport(t, "A", "a.>", "B")
+	ts.AddImport(t, "A", "q", "B")
+
+	_, stderr, err := ExecuteCmd(createCheckUnusedExportsCmd(), "--operator", "O")
+	require.NoError(t, err)
+	stderr = StripTableDecorations(stderr)
+	require.Contains(t, stderr, "A orphan.> stream orphan.>")
+	require.NotContains(t, stderr, "a.>")
+	require.NotContains(t, stderr, "q")
+}
+
+func Test_CheckUnusedExportsNone(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	ts.AddAccount(t, "B")
+	ts.AddImport(t, "A", "a.>", "B")
+
+	_, stderr, err := ExecuteCmd(createCheckUnusedExportsCmd())
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No unused exports")
+}
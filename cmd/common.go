@@ -177,6 +177,37 @@ func ParseNumber(s string) (int64, error) {
 	return 0, fmt.Errorf("couldn't parse number: %v", s)
 }
 
+// ParseDataSize parses a size in bytes where the K, M and G multipliers are
+// binary, 1Kb is 1024 bytes. The multiplier can be followed by b or ib.
+func ParseDataSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	re := regexp.MustCompile(`^(-?\d+)\s*(B|[KMG]I?B?)?$`)
+	m := re.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("couldn't parse size: %v", s)
+	}
+	v, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if m[2] == "" || m[2] == "B" {
+		return v, nil
+	}
+	if v < 0 {
+		return -1, nil
+	}
+	switch m[2][0] {
+	case 'K':
+		return v * 1024, nil
+	case 'M':
+		return v * 1024 * 1024, nil
+	default:
+		return v * 1024 * 1024 * 1024, nil
+	}
+}
+
 func UnixToDate(d int64) string {
 	if d == 0 {
 		return ""
@@ -193,6 +193,42 @@ func TestCommon_ParseNumber(t *testing.T) {
 	}
 }
 
+func TestCommon_ParseDataSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		output  int64
+		isError bool
+	}{
+		{"", 0, false},
+		{"-1", -1, false},
+		{"1000", 1000, false},
+		{"10B", 10, false},
+		{"1K", 1024, false},
+		{"1Kb", 1024, false},
+		{"1Mb", 1048576, false},
+		{"1mb", 1048576, false},
+		{"1MiB", 1048576, false},
+		{"2Gb", 2147483648, false},
+		{"-1Mb", -1, false},
+		{"1Tb", 0, true},
+		{"lots", 0, true},
+	}
+	for _, d := range tests {
+		v, err := ParseDataSize(d.input)
+		if err != nil && !d.isError {
+			t.Errorf("%s didn't expect error: %v", d.input, err)
+			continue
+		}
+		if err == nil && d.isError {
+			t.Errorf("expected error from %s", d.input)
+			continue
+		}
+		if v != d.output {
+			t.Errorf("%s expected %d but got %d", d.input, d.output, v)
+		}
+	}
+}
+
 func TestCommon_NKeyValidatorActualKey(t *testing.T) {
 	as, _, _ := CreateAccountKey(t)
 	fn := NKeyValidator(nkeys.PrefixByteAccount)
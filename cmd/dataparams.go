@@ -22,6 +22,15 @@ import (
 type DataParams struct {
 	Value  string
 	Number int64
+	// Binary parses the K, M and G multipliers as powers of 1024
+	Binary bool
+}
+
+func (e *DataParams) parse(s string) (int64, error) {
+	if e.Binary {
+		return ParseDataSize(s)
+	}
+	return ParseNumber(s)
 }
 
 func (e *DataParams) Valid() error {
@@ -33,7 +42,7 @@ func (e *DataParams) Edit(prompt string) error {
 	var err error
 	var nv int64
 	sv, err := cli.Prompt(prompt, e.Value, cli.Val(func(s string) error {
-		nv, err = ParseNumber(s)
+		nv, err = e.parse(s)
 		return err
 	}))
 	if err != nil {
@@ -45,5 +54,5 @@ func (e *DataParams) Edit(prompt string) error {
 }
 
 func (e *DataParams) NumberValue() (int64, error) {
-	return ParseNumber(e.Value)
+	return e.parse(e.Value)
 }
@@ -254,6 +254,13 @@ func (p *DeleteAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 		r.AddOK("deleted account")
 	}
 
+	// nsc settings for the account
+	if s.Has(store.Accounts, p.AccountContextParams.Name, store.AccountDefaultsFile) {
+		if err := s.Delete(store.Accounts, p.AccountContextParams.Name, store.AccountDefaultsFile); err != nil {
+			r.AddFromError(err)
+		}
+	}
+
 	if err := s.Delete(store.Accounts, p.AccountContextParams.Name); err != nil {
 		r.AddFromError(err)
 	} else {
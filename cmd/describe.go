@@ -16,11 +16,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+
 	"github.com/spf13/cobra"
 )
 
 var Raw bool
 var WideFlag bool
+var RedactKeys bool
 var Wide = noopNameFilter
 
 type WideFun = func(a string) string
@@ -48,6 +54,9 @@ var describeCmd = &cobra.Command{
 	Short: "Describe assets such as operators, accounts, users, and jwt files",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		var err error
+		if RedactKeys && Raw {
+			return errors.New("--redact-keys and --raw are exclusive")
+		}
 		if WideFlag {
 			Wide = noopNameFilter
 		} else {
@@ -64,4 +73,35 @@ func init() {
 	GetRootCmd().AddCommand(describeCmd)
 	describeCmd.PersistentFlags().BoolVarP(&WideFlag, "long-ids", "W", false, "display account ids on imports")
 	describeCmd.PersistentFlags().BoolVarP(&Raw, "raw", "R", false, "output the raw JWT (exclusive of long-ids)")
+	describeCmd.PersistentFlags().BoolVarP(&RedactKeys, "redact-keys", "", false, "truncate public keys so the output can be shared (exclusive of raw)")
+}
+
+// publicKeyRe matches operator, account, user, server, cluster and curve public keys
+var publicKeyRe = regexp.MustCompile(`\b[OAUNCX][A-Z2-7]{55}\b`)
+
+const redactedKeyLen = 8
+
+func redactKey(pk []byte) []byte {
+	return append(pk[:redactedKeyLen:redactedKeyLen], "..."...)
+}
+
+// redactClaims replaces the public keys in the claims pointed to by c with
+// a short prefix when --redact-keys is set, so the descriptions are
+// rendered from the redacted values
+func redactClaims(c interface{}) error {
+	if !RedactKeys {
+		return nil
+	}
+	d, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	d = publicKeyRe.ReplaceAllFunc(d, redactKey)
+	// decode into a new value, maps keyed by public keys would otherwise be merged
+	v := reflect.New(reflect.TypeOf(c).Elem())
+	if err := json.Unmarshal(d, v.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(c).Elem().Set(v.Elem())
+	return nil
 }
@@ -16,6 +16,9 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/spf13/cobra"
@@ -28,12 +31,16 @@ func createDescribeAccountCmd() *cobra.Command {
 		Short:        "Describes an account",
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
+		Example: `nsc describe account --name A
+# exports, imports, limits and signing keys as json
+nsc describe account --name A --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return RunAction(cmd, args, &params)
 		},
 	}
 	cmd.Flags().StringVarP(&params.outputFile, "output-file", "o", "--", "output file, '--' is stdout")
 	cmd.Flags().StringVarP(&params.AccountContextParams.Name, "name", "n", "", "account name")
+	cmd.Flags().BoolVarP(&params.json, "json", "", false, "output the decoded account claims as json")
 
 	return cmd
 }
@@ -47,6 +54,7 @@ type DescribeAccountParams struct {
 	jwt.AccountClaims
 	outputFile string
 	raw        []byte
+	json       bool
 }
 
 func (p *DescribeAccountParams) SetDefaults(ctx ActionCtx) error {
@@ -77,12 +85,18 @@ func (p *DescribeAccountParams) Load(ctx ActionCtx) error {
 			return err
 		}
 		p.AccountClaims = *ac
+		if err := redactClaims(&p.AccountClaims); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (p *DescribeAccountParams) Validate(ctx ActionCtx) error {
+	if p.json && Raw {
+		return errors.New("specify only one of --json or --raw")
+	}
 	return nil
 }
 
@@ -103,6 +117,15 @@ func (p *DescribeAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 		if err := Write(p.outputFile, p.raw); err != nil {
 			return nil, err
 		}
+	} else if p.json {
+		d, err := json.MarshalIndent(p.AccountClaims, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		d = append(d, '\n')
+		if err := Write(p.outputFile, d); err != nil {
+			return nil, err
+		}
 	} else {
 		v := NewAccountDescriber(p.AccountClaims).Describe()
 		if err := Write(p.outputFile, []byte(v)); err != nil {
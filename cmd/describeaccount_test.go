@@ -16,6 +16,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/nats-io/jwt"
@@ -166,3 +167,48 @@ func TestDescribeAccount_Latency(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, out, "lat (10%)")
 }
+
+func TestDescribeAccount_Exports(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Stream, "stream.>", true)
+	_, _, err := ExecuteCmd(createAddExportCmd(), "--account", "A", "--subject", "svc.q", "--service", "--private", "--response-type", jwt.ResponseTypeStream)
+	require.NoError(t, err)
+
+	stdout, _, err := ExecuteCmd(createDescribeAccountCmd(), "--name", "A")
+	require.NoError(t, err)
+	stdout = StripTableDecorations(stdout)
+	require.Regexp(t, `stream\.> +Stream +stream\.> +Yes`, stdout)
+	require.Regexp(t, `svc\.q +Service \[Stream\] +svc\.q +No`, stdout)
+
+	stdout, _, err = ExecuteCmd(createDescribeAccountCmd(), "--name", "A", "--json")
+	require.NoError(t, err)
+	var ac jwt.AccountClaims
+	require.NoError(t, json.Unmarshal([]byte(stdout), &ac))
+	require.Len(t, ac.Exports, 2)
+	for _, e := range ac.Exports {
+		switch string(e.Subject) {
+		case "stream.>":
+			require.Equal(t, jwt.Stream, e.Type)
+			require.False(t, e.TokenReq)
+		case "svc.q":
+			require.Equal(t, jwt.Service, e.Type)
+			require.True(t, e.TokenReq)
+			require.EqualValues(t, jwt.ResponseTypeStream, e.ResponseType)
+		default:
+			t.Fatalf("unexpected export %q", e.Subject)
+		}
+	}
+	require.Contains(t, stdout, `"type": "stream"`)
+	require.Contains(t, stdout, `"type": "service"`)
+
+	oldRaw := Raw
+	Raw = true
+	defer func() {
+		Raw = oldRaw
+	}()
+	_, _, err = ExecuteCmd(createDescribeAccountCmd(), "--name", "A", "--json")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify only one of --json or --raw")
+}
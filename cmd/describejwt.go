@@ -116,24 +116,36 @@ func (p *DescribeFile) Run(ctx ActionCtx) (store.Status, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := redactClaims(ac); err != nil {
+			return nil, err
+		}
 		describer = NewAccountDescriber(*ac)
 	case jwt.ActivationClaim:
 		ac, err := jwt.DecodeActivationClaims(p.token)
 		if err != nil {
 			return nil, err
 		}
+		if err := redactClaims(ac); err != nil {
+			return nil, err
+		}
 		describer = NewActivationDescriber(*ac)
 	case jwt.UserClaim:
 		uc, err := jwt.DecodeUserClaims(p.token)
 		if err != nil {
 			return nil, err
 		}
+		if err := redactClaims(uc); err != nil {
+			return nil, err
+		}
 		describer = NewUserDescriber(*uc)
 	case jwt.OperatorClaim:
 		oc, err := jwt.DecodeOperatorClaims(p.token)
 		if err != nil {
 			return nil, err
 		}
+		if err := redactClaims(oc); err != nil {
+			return nil, err
+		}
 		describer = NewOperatorDescriber(*oc)
 	}
 
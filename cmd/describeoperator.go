@@ -16,12 +16,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
 )
 
 func createDescribeOperatorCmd() *cobra.Command {
@@ -37,6 +44,12 @@ func createDescribeOperatorCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&params.outputFile, "output-file", "o", "--", "output file, '--' is stdout")
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "operator name")
+	cmd.Flags().BoolVarP(&params.importsGraph, "imports-graph", "", false, "describe the import/export relationships between the accounts of the operator")
+	cmd.Flags().StringVarP(&params.format, "format", "", "dot", "format of the imports graph (dot)")
+	cmd.Flags().BoolVarP(&params.verifyServiceURLs, "verify-service-urls", "", false, "check that a tcp connection can be made to each operator service url")
+	cmd.Flags().BoolVarP(&params.tree, "tree", "", false, "describe the operator, its accounts and their users as a tree")
+	cmd.Flags().BoolVarP(&params.json, "json", "", false, "print the tree as JSON (requires --tree)")
+	cmd.Flags().DurationVarP(&params.timeout, "timeout", "", 2*time.Second, "time to wait for each service url connection (requires --verify-service-urls)")
 
 	return cmd
 }
@@ -50,6 +63,17 @@ type DescribeOperatorParams struct {
 	outputFile string
 	claim      jwt.OperatorClaims
 	raw        []byte
+
+	importsGraph bool
+	format       string
+	accounts     []*jwt.AccountClaims
+
+	verifyServiceURLs bool
+	timeout           time.Duration
+
+	tree     bool
+	json     bool
+	treeRoot *treeNode
 }
 
 func (p *DescribeOperatorParams) SetDefaults(ctx ActionCtx) error {
@@ -83,6 +107,14 @@ func (p *DescribeOperatorParams) PreInteractive(ctx ActionCtx) error {
 func (p *DescribeOperatorParams) Load(ctx ActionCtx) error {
 	var err error
 
+	if p.importsGraph {
+		return p.loadAccounts(ctx)
+	}
+	if p.tree {
+		p.treeRoot, err = loadOperatorTree(ctx.StoreCtx().Store)
+		return err
+	}
+
 	if Raw {
 		p.raw, err = ctx.StoreCtx().Store.ReadRawOperatorClaim()
 		if err != nil {
@@ -94,11 +126,57 @@ func (p *DescribeOperatorParams) Load(ctx ActionCtx) error {
 			return err
 		}
 		p.claim = *oc
+		if err := redactClaims(&p.claim); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *DescribeOperatorParams) loadAccounts(ctx ActionCtx) error {
+	s := ctx.StoreCtx().Store
+	names, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		ac, err := s.ReadAccountClaim(n)
+		if err != nil {
+			return err
+		}
+		if err := redactClaims(ac); err != nil {
+			return err
+		}
+		p.accounts = append(p.accounts, ac)
 	}
 	return nil
 }
 
 func (p *DescribeOperatorParams) Validate(ctx ActionCtx) error {
+	if ctx.CurrentCmd().Flags().Changed("format") && !p.importsGraph {
+		return errors.New("--format requires --imports-graph")
+	}
+	if ctx.CurrentCmd().Flags().Changed("timeout") && !p.verifyServiceURLs {
+		return errors.New("--timeout requires --verify-service-urls")
+	}
+	if p.verifyServiceURLs && (Raw || p.importsGraph) {
+		return errors.New("--verify-service-urls is exclusive of --raw and --imports-graph")
+	}
+	if p.json && !p.tree {
+		return errors.New("--json requires --tree")
+	}
+	if p.tree && (Raw || p.importsGraph || p.verifyServiceURLs) {
+		return errors.New("--tree is exclusive of --raw, --imports-graph and --verify-service-urls")
+	}
+	if p.importsGraph {
+		if Raw {
+			return errors.New("--raw and --imports-graph are exclusive")
+		}
+		if p.format != "dot" {
+			return fmt.Errorf("unsupported imports graph format %q", p.format)
+		}
+	}
 	return nil
 }
 
@@ -107,6 +185,18 @@ func (p *DescribeOperatorParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *DescribeOperatorParams) Run(ctx ActionCtx) (store.Status, error) {
+	if p.importsGraph {
+		if err := Write(p.outputFile, []byte(importsGraphDot(ctx.StoreCtx().Operator.Name, p.accounts))); err != nil {
+			return nil, err
+		}
+		if !IsStdOut(p.outputFile) {
+			return store.OKStatus("wrote imports graph to %q", AbbrevHomePaths(p.outputFile)), nil
+		}
+		return nil, nil
+	}
+	if p.tree {
+		return p.writeTree()
+	}
 	if Raw {
 		if !IsStdOut(p.outputFile) {
 			var err error
@@ -121,6 +211,9 @@ func (p *DescribeOperatorParams) Run(ctx ActionCtx) (store.Status, error) {
 		}
 	} else {
 		v := NewOperatorDescriber(p.claim).Describe()
+		if p.verifyServiceURLs {
+			v += describeServiceURLReachability(checkServiceURLs(p.claim.OperatorServiceURLs, p.timeout))
+		}
 		data := []byte(v)
 		if err := Write(p.outputFile, data); err != nil {
 			return nil, err
@@ -136,3 +229,171 @@ func (p *DescribeOperatorParams) Run(ctx ActionCtx) (store.Status, error) {
 	}
 	return s, nil
 }
+
+type treeNode struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`
+	PublicKey string      `json:"public_key"`
+	Children  []*treeNode `json:"children,omitempty"`
+}
+
+// loadOperatorTree reads the operator with its accounts and their users,
+// accounts without users are leaves of the tree
+func loadOperatorTree(s *store.Store) (*treeNode, error) {
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return nil, err
+	}
+	root := &treeNode{Kind: "operator", Name: s.Info.Name, PublicKey: oc.Subject}
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(accounts)
+	for _, an := range accounts {
+		ac, err := s.ReadAccountClaim(an)
+		if err != nil {
+			return nil, err
+		}
+		a := &treeNode{Kind: "account", Name: an, PublicKey: ac.Subject}
+		users, err := s.ListEntries(store.Accounts, an, store.Users)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(users)
+		for _, un := range users {
+			uc, err := s.ReadUserClaim(an, un)
+			if err != nil {
+				return nil, err
+			}
+			a.Children = append(a.Children, &treeNode{Kind: "user", Name: un, PublicKey: uc.Subject})
+		}
+		root.Children = append(root.Children, a)
+	}
+	return root, nil
+}
+
+// redact shortens the public keys of the tree when --redact-keys is set
+func (n *treeNode) redact() {
+	n.PublicKey = string(redactKey([]byte(n.PublicKey)))
+	for _, c := range n.Children {
+		c.redact()
+	}
+}
+
+// render writes the node and its children indented by their depth, the
+// public keys are truncated
+func (n *treeNode) render(buf *strings.Builder, depth int) {
+	pk := n.PublicKey
+	if len(pk) > redactedKeyLen {
+		pk = string(redactKey([]byte(pk)))
+	}
+	buf.WriteString(fmt.Sprintf("%s%s %s (%s)\n", strings.Repeat("  ", depth), n.Kind, n.Name, pk))
+	for _, c := range n.Children {
+		c.render(buf, depth+1)
+	}
+}
+
+func (p *DescribeOperatorParams) writeTree() (store.Status, error) {
+	var data []byte
+	if p.json {
+		if RedactKeys {
+			p.treeRoot.redact()
+		}
+		d, err := json.MarshalIndent(p.treeRoot, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		data = append(d, '\n')
+	} else {
+		var buf strings.Builder
+		p.treeRoot.render(&buf, 0)
+		data = []byte(buf.String())
+	}
+	if err := Write(p.outputFile, data); err != nil {
+		return nil, err
+	}
+	if !IsStdOut(p.outputFile) {
+		return store.OKStatus("wrote operator tree to %q", AbbrevHomePaths(p.outputFile)), nil
+	}
+	return nil, nil
+}
+
+// importsGraphDot renders a Graphviz graph with an edge from the exporting
+// account to every account importing from it
+func importsGraphDot(operator string, accounts []*jwt.AccountClaims) string {
+	names := make(map[string]string)
+	for _, ac := range accounts {
+		names[ac.Subject] = ac.Name
+	}
+	node := func(pk string) string {
+		if n, ok := names[pk]; ok {
+			return n
+		}
+		return pk
+	}
+
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("digraph %q {\n", operator))
+	for _, ac := range accounts {
+		buf.WriteString(fmt.Sprintf("  %q;\n", ac.Name))
+	}
+	for _, ac := range accounts {
+		for _, im := range ac.Imports {
+			label := fmt.Sprintf("%s %s", im.Type.String(), importRemoteSubject(im))
+			buf.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", node(im.Account), ac.Name, label))
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+type serviceURLStatus struct {
+	url string
+	err error
+}
+
+// checkServiceURLs dials each url, nats servers negotiate tls after the
+// connection is made, so a tcp connection is what can be verified
+func checkServiceURLs(urls []string, timeout time.Duration) []serviceURLStatus {
+	var statuses []serviceURLStatus
+	for _, v := range urls {
+		statuses = append(statuses, serviceURLStatus{url: v, err: dialServiceURL(v, timeout)})
+	}
+	return statuses
+}
+
+func dialServiceURL(v string, timeout time.Duration) error {
+	u, err := url.Parse(v)
+	if err != nil {
+		return err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	c, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return err
+	}
+	return c.Close()
+}
+
+func describeServiceURLReachability(statuses []serviceURLStatus) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Service URL Reachability")
+	if len(statuses) == 0 {
+		table.AddRow("No service URLs")
+		return table.Render()
+	}
+	table.AddHeaders("URL", "Reachable", "Error")
+	for _, s := range statuses {
+		msg := ""
+		if s.err != nil {
+			msg = s.err.Error()
+		}
+		table.AddRow(s.url, yesNo(s.err == nil), msg)
+	}
+	return table.Render()
+}
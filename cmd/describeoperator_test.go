@@ -16,7 +16,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nats-io/jwt"
 
@@ -149,3 +154,90 @@ func TestDescribeOperator_OperatorServiceURLs(t *testing.T) {
 	require.Contains(t, stdout, "nats://localhost:4222")
 	require.Contains(t, stdout, "tls://localhost:4333")
 }
+
+func TestDescribeOperator_ImportsGraph(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	ts.AddAccount(t, "B")
+	ts.AddImport(t, "A", "a.>", "B")
+
+	stdout, _, err := ExecuteCmd(createDescribeOperatorCmd(), "--imports-graph", "--format", "dot")
+	require.NoError(t, err)
+	require.Contains(t, stdout, `digraph "O" {`)
+	require.Contains(t, stdout, `"A" -> "B" [label="stream a.>"];`)
+
+	_, _, err = ExecuteCmd(createDescribeOperatorCmd(), "--imports-graph", "--format", "svg")
+	require.Error(t, err)
+}
+
+func TestDescribeOperator_VerifyServiceURLs(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	// a port that nothing listens on
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	live := "nats://" + l.Addr().String()
+	down := "tls://" + dead
+	_, _, err = ExecuteCmd(createEditOperatorCmd(), "--service-url", live, "--service-url", down)
+	require.NoError(t, err)
+
+	stdout, _, err := ExecuteCmd(createDescribeOperatorCmd(), "--verify-service-urls", "--timeout", "500ms")
+	require.NoError(t, err)
+	out := StripTableDecorations(stdout)
+	require.Contains(t, out, "Service URL Reachability")
+	require.Contains(t, out, live+" Yes")
+	require.Contains(t, out, down+" No")
+
+	statuses := checkServiceURLs([]string{live, down}, 500*time.Millisecond)
+	require.Len(t, statuses, 2)
+	require.NoError(t, statuses[0].err)
+	require.Error(t, statuses[1].err)
+
+	_, _, err = ExecuteCmd(createDescribeOperatorCmd(), "--timeout", "1s")
+	require.Error(t, err)
+}
+
+func TestDescribeOperator_Tree(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "u1")
+	ts.AddUser(t, "A", "u2")
+	ts.AddAccount(t, "B")
+
+	stdout, _, err := ExecuteCmd(createDescribeOperatorCmd(), "--tree")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 5)
+	apk := ts.GetAccountPublicKey(t, "A")
+	require.Equal(t, fmt.Sprintf("  account A (%s...)", apk[:redactedKeyLen]), lines[1])
+	require.True(t, strings.HasPrefix(lines[2], "    user u1 ("))
+	require.True(t, strings.HasPrefix(lines[4], "  account B ("))
+	require.NotContains(t, stdout, apk)
+
+	stdout, _, err = ExecuteCmd(createDescribeOperatorCmd(), "--tree", "--json")
+	require.NoError(t, err)
+	var root treeNode
+	require.NoError(t, json.Unmarshal([]byte(stdout), &root))
+	require.Equal(t, "operator", root.Kind)
+	require.Equal(t, ts.GetOperatorPublicKey(t), root.PublicKey)
+	require.Len(t, root.Children, 2)
+	require.Equal(t, apk, root.Children[0].PublicKey)
+	require.Len(t, root.Children[0].Children, 2)
+	require.Equal(t, "user", root.Children[0].Children[1].Kind)
+	require.Empty(t, root.Children[0].Children[0].Children)
+	require.Equal(t, "B", root.Children[1].Name)
+	require.Empty(t, root.Children[1].Children)
+
+	_, _, err = ExecuteCmd(createDescribeOperatorCmd(), "--json")
+	require.Error(t, err)
+}
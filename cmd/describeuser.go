@@ -16,12 +16,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/nsc/cmd/store"
 
 	"github.com/nats-io/jwt"
 	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
 )
 
 func createDescribeUserCmd() *cobra.Command {
@@ -37,6 +40,9 @@ func createDescribeUserCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&params.outputFile, "output-file", "o", "--", "output file, '--' is stdout")
 	cmd.Flags().StringVarP(&params.user, "name", "n", "", "user name")
+	cmd.Flags().BoolVarP(&params.credsPath, "creds-path", "", false, "print only the path to the user creds file")
+	cmd.Flags().BoolVarP(&params.json, "json", "", false, "output the decoded user claims as json")
+	cmd.Flags().BoolVarP(&params.showResolvedIssuer, "show-resolved-issuer", "", false, "show the account the issuer resolves to and whether the issuer is trusted by it")
 	params.AccountContextParams.BindFlags(cmd)
 
 	return cmd
@@ -52,6 +58,20 @@ type DescribeUserParams struct {
 	user       string
 	outputFile string
 	raw        []byte
+	credsPath  bool
+	json       bool
+
+	showResolvedIssuer bool
+	resolved           resolvedIssuer
+}
+
+type resolvedIssuer struct {
+	signer        string
+	signingKey    bool
+	issuerAccount string
+	account       string
+	accountKey    string
+	reason        string
 }
 
 func (p *DescribeUserParams) SetDefaults(ctx ActionCtx) error {
@@ -93,6 +113,10 @@ func (p *DescribeUserParams) Load(ctx ActionCtx) error {
 		return fmt.Errorf("user is required")
 	}
 
+	if !ctx.StoreCtx().Store.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.user)) {
+		return fmt.Errorf("user %q not found in account %q", p.user, p.AccountContextParams.Name)
+	}
+
 	if Raw {
 		p.raw, err = ctx.StoreCtx().Store.ReadRawUserClaim(p.AccountContextParams.Name, p.user)
 		if err != nil {
@@ -104,11 +128,71 @@ func (p *DescribeUserParams) Load(ctx ActionCtx) error {
 			return err
 		}
 		p.UserClaims = *uc
+		if p.showResolvedIssuer {
+			if err := p.resolveIssuer(ctx, uc); err != nil {
+				return err
+			}
+		}
+		if err := redactClaims(&p.UserClaims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveIssuer follows the issuer of the user to the account identity,
+// the issuer is trusted if it is the account or one of its signing keys
+func (p *DescribeUserParams) resolveIssuer(ctx ActionCtx, uc *jwt.UserClaims) error {
+	ac, err := ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	p.resolved = resolvedIssuer{
+		signer:        uc.Issuer,
+		signingKey:    uc.Issuer != ac.Subject,
+		issuerAccount: uc.IssuerAccount,
+		account:       p.AccountContextParams.Name,
+		accountKey:    ac.Subject,
+		reason:        orphanReason(ac, uc),
+	}
+	if RedactKeys {
+		p.resolved.signer = string(redactKey([]byte(p.resolved.signer)))
+		p.resolved.accountKey = string(redactKey([]byte(p.resolved.accountKey)))
+		if p.resolved.issuerAccount != "" {
+			p.resolved.issuerAccount = string(redactKey([]byte(p.resolved.issuerAccount)))
+		}
 	}
 	return nil
 }
 
+func (r resolvedIssuer) Describe() string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Resolved Issuer")
+	kind := "Account Identity"
+	if r.signingKey {
+		kind = "Account Signing Key"
+	}
+	table.AddRow("Signed By", r.signer)
+	table.AddRow("Signer Kind", kind)
+	if r.issuerAccount != "" {
+		table.AddRow("Issuer Account", r.issuerAccount)
+	}
+	table.AddRow("Resolved Account", fmt.Sprintf("%s (%s)", r.account, r.accountKey))
+	table.AddRow("Trusted", yesNo(r.reason == ""))
+	if r.reason != "" {
+		table.AddRow("Reason", r.reason)
+	}
+	return table.Render()
+}
+
 func (p *DescribeUserParams) Validate(ctx ActionCtx) error {
+	if p.json && Raw {
+		return errors.New("specify only one of --json or --raw")
+	}
+	if p.showResolvedIssuer && (p.json || Raw) {
+		return errors.New("--show-resolved-issuer is exclusive of --json and --raw")
+	}
 	return nil
 }
 
@@ -117,6 +201,13 @@ func (p *DescribeUserParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *DescribeUserParams) Run(ctx ActionCtx) (store.Status, error) {
+	if p.credsPath {
+		fp := ctx.StoreCtx().KeyStore.GetUserCredsPath(p.AccountContextParams.Name, p.user)
+		if fp == "" {
+			return nil, fmt.Errorf("no creds file found for user %q in account %q", p.user, p.AccountContextParams.Name)
+		}
+		return nil, Write(p.outputFile, []byte(fp+"\n"))
+	}
 	if Raw {
 		if !IsStdOut(p.outputFile) {
 			var err error
@@ -129,8 +220,20 @@ func (p *DescribeUserParams) Run(ctx ActionCtx) (store.Status, error) {
 		if err := Write(p.outputFile, p.raw); err != nil {
 			return nil, err
 		}
+	} else if p.json {
+		d, err := json.MarshalIndent(p.UserClaims, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		d = append(d, '\n')
+		if err := Write(p.outputFile, d); err != nil {
+			return nil, err
+		}
 	} else {
 		v := NewUserDescriber(p.UserClaims).Describe()
+		if p.showResolvedIssuer {
+			v += p.resolved.Describe()
+		}
 		if err := Write(p.outputFile, []byte(v)); err != nil {
 			return nil, err
 		}
@@ -18,6 +18,8 @@
 package cmd
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/nats-io/jwt"
@@ -202,3 +204,111 @@ func TestDescribeRawUser(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, stdout, "Issuer Account")
 }
+
+func TestDescribeUser_CredsPath(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	stdout, _, err := ExecuteCmd(createDescribeUserCmd(), "U", "--creds-path")
+	require.NoError(t, err)
+	require.Equal(t, ts.KeyStore.CalcUserCredsPath("A", "U"), strings.TrimSpace(stdout))
+
+	_, pk, _ := CreateUserKey(t)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--public-key", pk)
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createDescribeUserCmd(), "V", "--creds-path")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `no creds file found for user "V"`)
+}
+
+func TestDescribeUser_Json(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub", "foo.>", "--allow-sub", "bar")
+	require.NoError(t, err)
+
+	stdout, _, err := ExecuteCmd(createDescribeUserCmd(), "U", "--json")
+	require.NoError(t, err)
+	var uc jwt.UserClaims
+	require.NoError(t, json.Unmarshal([]byte(stdout), &uc))
+	require.Equal(t, ts.GetUserPublicKey(t, "A", "U"), uc.Subject)
+	require.ElementsMatch(t, uc.Pub.Allow, []string{"foo.>"})
+	require.ElementsMatch(t, uc.Sub.Allow, []string{"bar"})
+
+	stdout, _, err = ExecuteCmd(createDescribeUserCmd(), "U")
+	require.NoError(t, err)
+	require.Contains(t, stdout, "foo.>")
+	require.Contains(t, stdout, "bar")
+
+	_, _, err = ExecuteCmd(createDescribeUserCmd(), "X")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `user "X" not found in account "A"`)
+}
+
+func TestDescribeUserRedactKeys(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	old := RedactKeys
+	RedactKeys = true
+	defer func() {
+		RedactKeys = old
+	}()
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	apk := ts.GetAccountPublicKey(t, "A")
+	upk := ts.GetUserPublicKey(t, "A", "U")
+
+	stdout, _, err := ExecuteCmd(createDescribeUserCmd(), "U")
+	require.NoError(t, err)
+	require.NotContains(t, stdout, upk)
+	require.NotContains(t, stdout, apk)
+	require.Contains(t, stdout, upk[:redactedKeyLen]+"...")
+	require.Contains(t, stdout, apk[:redactedKeyLen]+"...")
+
+	stdout, _, err = ExecuteCmd(createDescribeUserCmd(), "U", "--json")
+	require.NoError(t, err)
+	var uc jwt.UserClaims
+	require.NoError(t, json.Unmarshal([]byte(stdout), &uc))
+	require.Equal(t, "U", uc.Name)
+	require.Equal(t, upk[:redactedKeyLen]+"...", uc.Subject)
+	require.Equal(t, apk[:redactedKeyLen]+"...", uc.Issuer)
+}
+
+func TestDescribeUserShowResolvedIssuer(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+
+	seed, spk, _ := CreateAccountKey(t)
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", spk)
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U", "--signing-key", string(seed))
+	require.NoError(t, err)
+
+	stdout, _, err := ExecuteCmd(createDescribeUserCmd(), "U", "--show-resolved-issuer")
+	require.NoError(t, err)
+	out := StripTableDecorations(stdout)
+	require.Contains(t, out, "Signed By "+spk)
+	require.Contains(t, out, "Signer Kind Account Signing Key")
+	require.Contains(t, out, "Issuer Account "+apk)
+	require.Contains(t, out, "Resolved Account A ("+apk+")")
+	require.Contains(t, out, "Trusted Yes")
+
+	// the user is no longer trusted once the signing key is removed
+	_, _, err = ExecuteCmd(createEditAccount(), "--rm-sk", spk)
+	require.NoError(t, err)
+	stdout, _, err = ExecuteCmd(createDescribeUserCmd(), "U", "--show-resolved-issuer")
+	require.NoError(t, err)
+	out = StripTableDecorations(stdout)
+	require.Contains(t, out, "Trusted No")
+	require.Contains(t, out, "Reason issued by a signing key not in the account")
+
+	_, _, err = ExecuteCmd(createDescribeUserCmd(), "U", "--show-resolved-issuer", "--json")
+	require.Error(t, err)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nkeys"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func createEditAccount() *cobra.Command {
@@ -46,15 +47,33 @@ func createEditAccount() *cobra.Command {
 	cmd.Flags().StringVarP(&params.payload.Value, "payload", "", "-1", "set maximum message payload in bytes for the account (-1 is unlimited)")
 	cmd.Flags().Int64VarP(&params.subscriptions.NumberValue, "subscriptions", "", -1, "set maximum subscription for the account (-1 is unlimited)")
 	cmd.Flags().BoolVarP(&params.exportsWc, "wildcard-exports", "", true, "exports can contain wildcards")
+	cmd.Flags().BoolVarP(&params.rmServiceLatencyAll, "rm-service-latency-all", "", false, "remove the latency sampling from all service exports")
 	cmd.Flags().StringSliceVarP(&params.rmSigningKeys, "rm-sk", "", nil, "remove signing key - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.cloneLimitsFrom, "clone-limits-from", "", "", "copy the limits of the named account, limit flags override the copied limits")
+	cmd.Flags().StringVarP(&params.limitTemplate, "limit-template", "", "", "apply the limits of the named limit template, limit flags override the template")
+	cmd.Flags().StringVarP(&params.defaultUserExpiry, "default-user-expiry", "", "", "expiry applied to new users that don't specify one ('0' removes it) - #m(inutes), #h(ours), #d(ays), #w(eeks), #M(onths), #y(ears)")
 
 	cmd.Flags().StringVarP(&params.AccountContextParams.Name, "name", "n", "", "account to edit")
 	params.signingKeys.BindFlags("sk", "", nkeys.PrefixByteAccount, cmd)
 	params.TimeParams.BindFlags(cmd)
+	// accept the limit flag names used by add account
+	cmd.Flags().SetNormalizeFunc(editAccountFlagAliases)
 
 	return cmd
 }
 
+func editAccountFlagAliases(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "subs":
+		name = "subscriptions"
+	case "wildcards":
+		name = "wildcard-exports"
+	case "add-signing-key":
+		name = "sk"
+	}
+	return pflag.NormalizedName(name)
+}
+
 func init() {
 	editCmd.AddCommand(createEditAccount())
 }
@@ -75,6 +94,11 @@ type EditAccountParams struct {
 	data          DataParams
 	signingKeys   SigningKeysParams
 	rmSigningKeys []string
+
+	defaultUserExpiry   string
+	limitTemplate       string
+	cloneLimitsFrom     string
+	rmServiceLatencyAll bool
 }
 
 func (p *EditAccountParams) SetDefaults(ctx ActionCtx) error {
@@ -84,10 +108,14 @@ func (p *EditAccountParams) SetDefaults(ctx ActionCtx) error {
 	}
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
 
-	if !InteractiveFlag && ctx.NothingToDo("start", "expiry", "tag", "rm-tag", "conns", "leaf-conns", "exports", "imports", "subscriptions", "payload", "data", "wildcard-exports", "sk", "rm-sk") {
+	if !InteractiveFlag && ctx.NothingToDo("start", "expiry", "tag", "rm-tag", "conns", "leaf-conns", "exports", "imports", "subscriptions", "payload", "data", "wildcard-exports", "sk", "rm-sk", "default-user-expiry", "limit-template", "clone-limits-from", "rm-service-latency-all") {
 		ctx.CurrentCmd().SilenceUsage = false
 		return fmt.Errorf("specify an edit option")
 	}
+	if p.limitTemplate != "" && p.cloneLimitsFrom != "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("specify only one of --limit-template or --clone-limits-from")
+	}
 	return nil
 }
 
@@ -139,9 +167,87 @@ func (p *EditAccountParams) Load(ctx ActionCtx) error {
 		p.subscriptions.NumberValue = p.claim.Limits.Subs
 	}
 
+	if !ctx.CurrentCmd().Flags().Changed("wildcard-exports") {
+		p.exportsWc = p.claim.Limits.WildcardExports
+	}
+
+	if p.limitTemplate != "" {
+		return p.loadLimitTemplate(ctx)
+	}
+
+	if p.cloneLimitsFrom != "" {
+		return p.loadCloneLimits(ctx)
+	}
+
 	return err
 }
 
+// loadLimitTemplate sets the limits from the template that were not
+// specified as flags
+func (p *EditAccountParams) loadLimitTemplate(ctx ActionCtx) error {
+	templates, err := ctx.StoreCtx().Store.ReadLimitTemplates()
+	if err != nil {
+		return err
+	}
+	t := templates[p.limitTemplate]
+	if t == nil {
+		return fmt.Errorf("limit template %q not found", p.limitTemplate)
+	}
+	p.loadLimits(ctx, jwt.OperatorLimits{
+		Subs:            t.Subs,
+		Conn:            t.Conn,
+		LeafNodeConn:    t.LeafNodeConn,
+		Imports:         t.Imports,
+		Exports:         t.Exports,
+		Data:            t.Data,
+		Payload:         t.Payload,
+		WildcardExports: p.exportsWc,
+	})
+	return nil
+}
+
+// loadCloneLimits sets the limits from the source account that were not
+// specified as flags
+func (p *EditAccountParams) loadCloneLimits(ctx ActionCtx) error {
+	if !ctx.StoreCtx().Store.HasAccount(p.cloneLimitsFrom) {
+		return fmt.Errorf("account %q to clone limits from doesn't exist", p.cloneLimitsFrom)
+	}
+	src, err := ctx.StoreCtx().Store.ReadAccountClaim(p.cloneLimitsFrom)
+	if err != nil {
+		return err
+	}
+	p.loadLimits(ctx, src.Limits)
+	return nil
+}
+
+func (p *EditAccountParams) loadLimits(ctx ActionCtx, l jwt.OperatorLimits) {
+	flags := ctx.CurrentCmd().Flags()
+	if !flags.Changed("conns") {
+		p.conns.NumberValue = l.Conn
+	}
+	if !flags.Changed("leaf-conns") {
+		p.leafConns.NumberValue = l.LeafNodeConn
+	}
+	if !flags.Changed("data") {
+		p.data.Value = fmt.Sprintf("%d", l.Data)
+	}
+	if !flags.Changed("exports") {
+		p.exports.NumberValue = l.Exports
+	}
+	if !flags.Changed("imports") {
+		p.imports.NumberValue = l.Imports
+	}
+	if !flags.Changed("payload") {
+		p.payload.Value = fmt.Sprintf("%d", l.Payload)
+	}
+	if !flags.Changed("subscriptions") {
+		p.subscriptions.NumberValue = l.Subs
+	}
+	if !flags.Changed("wildcard-exports") {
+		p.exportsWc = l.WildcardExports
+	}
+}
+
 func (p *EditAccountParams) PostInteractive(ctx ActionCtx) error {
 	var err error
 	if err = p.signingKeys.Edit(); err != nil {
@@ -203,6 +309,9 @@ func (p *EditAccountParams) Validate(ctx ActionCtx) error {
 	if err = p.SignerParams.Resolve(ctx); err != nil {
 		return err
 	}
+	if _, err := ParseExpiry(p.defaultUserExpiry); err != nil {
+		return fmt.Errorf("default user expiry %q is invalid: %v", p.defaultUserExpiry, err)
+	}
 	return nil
 }
 
@@ -211,7 +320,18 @@ func (p *EditAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 	r.ReportSum = false
 
 	var err error
-	keys, _ := p.signingKeys.PublicKeys()
+	keys, err := p.signingKeys.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	ks := ctx.StoreCtx().KeyStore
+	skPaths, err := p.signingKeys.StoreGenerated(&ks)
+	if err != nil {
+		return nil, err
+	}
+	for _, fp := range skPaths {
+		r.AddOK("generated and stored account signing key %q", AbbrevHomePaths(fp))
+	}
 	if len(keys) > 0 {
 		p.claim.SigningKeys.Add(keys...)
 		for _, k := range keys {
@@ -228,6 +348,12 @@ func (p *EditAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 	}
 
 	flags := ctx.CurrentCmd().Flags()
+	if p.limitTemplate != "" {
+		r.AddOK("applied limit template %q", p.limitTemplate)
+	}
+	if p.cloneLimitsFrom != "" {
+		r.AddOK("cloned limits from account %q", p.cloneLimitsFrom)
+	}
 	p.claim.Limits.Conn = p.conns.NumberValue
 	if flags.Changed("conns") {
 		r.AddOK("changed max connections to %d", p.claim.Limits.Conn)
@@ -266,7 +392,7 @@ func (p *EditAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 		return nil, fmt.Errorf("error parsing %s: %s", "payload", p.data.Value)
 	}
 	if flags.Changed("payload") {
-		r.AddOK("changed max imports to %d", p.claim.Limits.Payload)
+		r.AddOK("changed max payload to %d bytes", p.claim.Limits.Payload)
 	}
 
 	p.claim.Limits.Subs = p.subscriptions.NumberValue
@@ -274,6 +400,37 @@ func (p *EditAccountParams) Run(ctx ActionCtx) (store.Status, error) {
 		r.AddOK("changed max subscriptions to %d", p.claim.Limits.Subs)
 	}
 
+	if p.rmServiceLatencyAll {
+		n := 0
+		for _, e := range p.claim.Exports {
+			if e.IsService() && e.Latency != nil {
+				e.Latency = nil
+				n++
+			}
+		}
+		r.AddOK("removed latency sampling from %d service exports", n)
+	}
+
+	if flags.Changed("default-user-expiry") {
+		s := ctx.StoreCtx().Store
+		d, err := s.ReadAccountDefaults(p.AccountContextParams.Name)
+		if err != nil {
+			return nil, err
+		}
+		d.UserExpiry = p.defaultUserExpiry
+		if d.UserExpiry == "0" {
+			d.UserExpiry = ""
+		}
+		if err := s.WriteAccountDefaults(p.AccountContextParams.Name, d); err != nil {
+			return nil, err
+		}
+		if d.UserExpiry == "" {
+			r.AddOK("removed default user expiry")
+		} else {
+			r.AddOK("changed default user expiry to %s", d.UserExpiry)
+		}
+	}
+
 	p.token, err = p.claim.Encode(p.signerKP)
 	if err != nil {
 		return nil, err
@@ -19,7 +19,10 @@ package cmd
 
 import (
 	"testing"
+	"time"
 
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
 	"github.com/stretchr/testify/require"
 )
 
@@ -147,3 +150,177 @@ func Test_EditAccountSigningKeys(t *testing.T) {
 	require.NoError(t, err)
 	require.NotContains(t, ac.SigningKeys, pk)
 }
+
+func Test_EditAccountGenerateSigningKey(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, stderr, err := ExecuteCmd(createEditAccount(), "--add-signing-key", "generate")
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 1)
+	sk := ac.SigningKeys[0]
+	require.True(t, nkeys.IsValidPublicAccountKey(sk))
+	require.Contains(t, stderr, sk)
+	require.Contains(t, stderr, "generated and stored account signing key")
+	require.True(t, ts.KeyStore.HasPrivateKey(sk))
+}
+
+func Test_EditAccountDefaultUserExpiry(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "--default-user-expiry", "90d")
+	require.NoError(t, err)
+
+	d, err := ts.Store.ReadAccountDefaults("A")
+	require.NoError(t, err)
+	require.Equal(t, "90d", d.UserExpiry)
+
+	before := time.Now().AddDate(0, 0, 90).Unix()
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.True(t, uc.Expires >= before && uc.Expires <= before+60)
+
+	// an explicit expiry wins
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "UU", "--expiry", "0")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "UU")
+	require.NoError(t, err)
+	require.Zero(t, uc.Expires)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "--default-user-expiry", "0")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "UUU")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "UUU")
+	require.NoError(t, err)
+	require.Zero(t, uc.Expires)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "--default-user-expiry", "30x")
+	require.Error(t, err)
+}
+
+func Test_EditAccountLimitTemplate(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditLimitTemplateCmd(), "medium", "--conns", "100", "--payload", "1K")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "--limit-template", "medium", "--subscriptions", "10")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), ac.Limits.Conn)
+	require.Equal(t, int64(1000), ac.Limits.Payload)
+	require.Equal(t, int64(10), ac.Limits.Subs)
+	require.Equal(t, int64(-1), ac.Limits.Data)
+
+	// flags override the template
+	_, _, err = ExecuteCmd(createEditAccount(), "--limit-template", "medium", "--conns", "5")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), ac.Limits.Conn)
+	require.Equal(t, int64(-1), ac.Limits.Subs)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "--limit-template", "large")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `limit template "large" not found`)
+}
+
+func Test_EditAccountCloneLimits(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "A", "--conns", "100", "--payload", "1K", "--wildcard-exports=false")
+	require.NoError(t, err)
+	src, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+
+	ts.AddAccount(t, "B")
+	_, _, err = ExecuteCmd(createEditAccount(), "B", "--clone-limits-from", "A")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Equal(t, src.Limits, ac.Limits)
+
+	// flags override the cloned limits
+	_, _, err = ExecuteCmd(createEditAccount(), "B", "--clone-limits-from", "A", "--payload", "2K")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Equal(t, int64(2000), ac.Limits.Payload)
+	require.Equal(t, int64(100), ac.Limits.Conn)
+
+	// editing something else keeps the wildcard setting
+	_, _, err = ExecuteCmd(createEditAccount(), "B", "--tag", "t")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.False(t, ac.Limits.WildcardExports)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "B", "--clone-limits-from", "A", "--limit-template", "x")
+	require.Error(t, err)
+}
+
+func Test_EditAccountChangedLimitsOnly(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "A", "--conns", "5", "--subs", "10", "--data", "1M", "--wildcards=false")
+	require.NoError(t, err)
+	before, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createEditAccount(), "A", "--conns", "50")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, int64(50), ac.Limits.Conn)
+	expected := before.Limits
+	expected.Conn = 50
+	require.Equal(t, expected, ac.Limits)
+
+	// add account flag names are accepted
+	_, _, err = ExecuteCmd(createEditAccount(), "A", "--subs", "20", "--wildcards")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, int64(20), ac.Limits.Subs)
+	require.True(t, ac.Limits.WildcardExports)
+	require.Equal(t, int64(50), ac.Limits.Conn)
+	require.Equal(t, int64(1000*1000), ac.Limits.Data)
+}
+
+func Test_EditAccountRmServiceLatencyAll(t *testing.T) {
+	ts := NewTestStore(t, "edit account")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createAddExportCmd(), "--subject", "q", "--service", "--latency", "lat.q", "--sampling", "50")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createAddExportCmd(), "--subject", "r", "--service", "--latency", "lat.r", "--sampling", "100")
+	require.NoError(t, err)
+	ts.AddExport(t, "A", jwt.Stream, "s.>", false)
+
+	_, stderr, err := ExecuteCmd(createEditAccount(), "--rm-service-latency-all")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "removed latency sampling from 2 service exports")
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Exports, 3)
+	for _, e := range ac.Exports {
+		require.Nil(t, e.Latency)
+	}
+}
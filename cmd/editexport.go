@@ -34,6 +34,11 @@ func createEditExportCmd() *cobra.Command {
 		Short:        "Edit an export",
 		Args:         MaxArgs(0),
 		SilenceUsage: true,
+		Example: `# Make a private export public, importers no longer need an activation token:
+nsc edit export --subject <subject> --public
+
+# Make a public export private:
+nsc edit export --subject <subject> --private`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return RunAction(cmd, args, &params)
 		},
@@ -43,12 +48,14 @@ func createEditExportCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&params.subject, "subject", "s", "", "subject")
 	cmd.Flags().BoolVarP(&params.service, "service", "r", false, "export type service")
 	cmd.Flags().BoolVarP(&params.private, "private", "p", false, "private export - requires an activation to access")
+	cmd.Flags().BoolVarP(&params.public, "public", "", false, "public export - doesn't require an activation to access (exclusive of --private)")
 	cmd.Flags().StringVarP(&params.latSubject, "latency", "", "", "latency metrics subject (services only)")
 	cmd.Flags().IntVarP(&params.latSampling, "sampling", "", 0, "latency sampling percentage [0-100] - 0 disables it (services only)")
 	cmd.Flags().BoolVarP(&params.rmLatencySampling, "rm-latency-sampling", "", false, "remove latency sampling")
 
 	hm := fmt.Sprintf("response type for the service [%s | %s | %s] (services only)", jwt.ResponseTypeSingleton, jwt.ResponseTypeStream, jwt.ResponseTypeChunked)
 	cmd.Flags().StringVarP(&params.responseType, "response-type", "", jwt.ResponseTypeSingleton, hm)
+	cmd.Flags().BoolVarP(&params.rmResponseType, "rm-response-type", "", false, "remove the response type, the service uses the default singleton responses (services only)")
 	params.AccountContextParams.BindFlags(cmd)
 
 	return cmd
@@ -70,13 +77,15 @@ type EditExportParams struct {
 	latSubject        string
 	service           bool
 	private           bool
+	public            bool
 	responseType      string
 	rmLatencySampling bool
+	rmResponseType    bool
 }
 
 func (p *EditExportParams) SetDefaults(ctx ActionCtx) error {
 	if !InteractiveFlag {
-		if ctx.NothingToDo("name", "subject", "service", "private", "latency", "sampling", "response-type") {
+		if ctx.NothingToDo("name", "subject", "service", "private", "public", "latency", "sampling", "response-type", "rm-response-type") {
 			return errors.New("please specify some options")
 		}
 	}
@@ -243,17 +252,31 @@ func (p *EditExportParams) Validate(ctx ActionCtx) error {
 	if p.subject == "" {
 		return errors.New("a subject is required")
 	}
+	if p.public && p.private {
+		return errors.New("specify only one of --public or --private")
+	}
 	if p.index == -1 {
 		return fmt.Errorf("no export with subject %q found", p.subject)
 	}
 
+	if p.rmResponseType {
+		if ctx.CurrentCmd().Flags().Changed("response-type") {
+			return errors.New("specify only one of --response-type or --rm-response-type")
+		}
+		if !p.service {
+			return errors.New("response types can only be removed from service exports")
+		}
+		if rt := p.claim.Exports[p.index].ResponseType; rt == "" || rt == jwt.ResponseTypeSingleton {
+			return fmt.Errorf("export %q doesn't have a response type set", p.subject)
+		}
+	}
+
 	if p.service {
-		rt := jwt.ResponseType(p.responseType)
-		if rt != jwt.ResponseTypeSingleton &&
-			rt != jwt.ResponseTypeStream &&
-			rt != jwt.ResponseTypeChunked {
-			return fmt.Errorf("unknown response type %q", p.responseType)
+		rt, err := parseResponseType(p.responseType)
+		if err != nil {
+			return err
 		}
+		p.responseType = string(rt)
 	}
 
 	if err = p.SignerParams.Resolve(ctx); err != nil {
@@ -282,7 +305,12 @@ func (p *EditExportParams) syncOptions(ctx ActionCtx) {
 	if !(cmd.Flag("name").Changed) {
 		p.name = old.Name
 	}
-	if !(cmd.Flag("private").Changed) {
+	if cmd.Flag("public").Changed {
+		// --public=false is the same as --private
+		if !p.public {
+			p.private = true
+		}
+	} else if !cmd.Flag("private").Changed {
 		p.private = old.TokenReq
 	}
 	sampling := 0
@@ -321,7 +349,11 @@ func (p *EditExportParams) Run(ctx ActionCtx) (store.Status, error) {
 
 	export.TokenReq = p.private
 	if export.TokenReq != old.TokenReq {
-		r.AddWarning("changed export to be private - this will break importers")
+		if export.TokenReq {
+			r.AddWarning("changed export to be private - this will break importers")
+		} else {
+			r.AddOK("changed export to be public - importers no longer need an activation token")
+		}
 	}
 	export.Subject = jwt.Subject(p.subject)
 	if export.Subject != old.Subject {
@@ -368,7 +400,12 @@ func (p *EditExportParams) Run(ctx ActionCtx) (store.Status, error) {
 		}
 
 		rt := jwt.ResponseType(p.responseType)
-		if old.ResponseType != rt {
+		// keep the existing response type, a blank response type is a singleton
+		export.ResponseType = p.claim.Exports[p.index].ResponseType
+		if p.rmResponseType {
+			export.ResponseType = ""
+			r.AddOK("removed response type - the service uses the default %s response type", jwt.ResponseTypeSingleton)
+		} else if old.ResponseType != rt {
 			export.ResponseType = rt
 			r.AddOK("changed response type to %s", p.responseType)
 		}
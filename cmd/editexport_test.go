@@ -37,6 +37,36 @@ func Test_EditExport_Private(t *testing.T) {
 	require.EqualValues(t, jwt.ResponseTypeChunked, ac.Exports[0].ResponseType)
 }
 
+func Test_EditExportPublic(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Service, "a", false)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.True(t, ac.Exports[0].TokenReq)
+
+	_, stderr, err := ExecuteCmd(createEditExportCmd(), "--subject", "a", "--public")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "changed export to be public")
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.False(t, ac.Exports[0].TokenReq)
+	require.Equal(t, jwt.Service, ac.Exports[0].Type)
+
+	_, stderr, err = ExecuteCmd(createEditExportCmd(), "--subject", "a", "--private")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "changed export to be private")
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.True(t, ac.Exports[0].TokenReq)
+
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "a", "--public", "--private")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify only one of --public or --private")
+}
+
 func Test_EditExport_Latency(t *testing.T) {
 	ts := NewTestStore(t, "O")
 	defer ts.Done(t)
@@ -125,3 +155,64 @@ func Test_EditExportNoExports(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "doesn't have exports")
 }
+
+func Test_EditExportRmResponseType(t *testing.T) {
+	ts := NewTestStore(t, "edit export")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Service, "q", true)
+	ts.AddExport(t, "A", jwt.Stream, "a.>", true)
+
+	_, _, err := ExecuteCmd(createEditExportCmd(), "--subject", "q", "--rm-response-type")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `export "q" doesn't have a response type set`)
+
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "q", "--response-type", jwt.ResponseTypeStream)
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, jwt.ResponseTypeStream, string(exportBySubject(ac, "q").ResponseType))
+
+	// editing another option keeps the response type
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "q", "--name", "svc")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, jwt.ResponseTypeStream, string(exportBySubject(ac, "q").ResponseType))
+
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "q", "--rm-response-type")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Empty(t, exportBySubject(ac, "q").ResponseType)
+
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "a.>", "--rm-response-type")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only be removed from service exports")
+}
+
+func exportBySubject(ac *jwt.AccountClaims, subject string) *jwt.Export {
+	for _, e := range ac.Exports {
+		if string(e.Subject) == subject {
+			return e
+		}
+	}
+	return nil
+}
+
+func Test_EditExportResponseTypeCaseInsensitive(t *testing.T) {
+	ts := NewTestStore(t, "edit export")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Service, "q", true)
+
+	_, _, err := ExecuteCmd(createEditExportCmd(), "--subject", "q", "--response-type", "stream")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.EqualValues(t, jwt.ResponseTypeStream, exportBySubject(ac, "q").ResponseType)
+
+	_, _, err = ExecuteCmd(createEditExportCmd(), "--subject", "q", "--response-type", "bogus")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown response type "bogus"`)
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createEditLimitTemplateCmd() *cobra.Command {
+	var params EditLimitTemplateParams
+	cmd := &cobra.Command{
+		Use:   "limit-template",
+		Short: "Create or edit a named set of account limits",
+		Example: `nsc edit limit-template medium --conns 100 --data 1G --payload 1M
+nsc edit account --name A --limit-template medium
+nsc edit limit-template medium --rm`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.name, "name", "n", "", "limit template name")
+	cmd.Flags().BoolVarP(&params.remove, "rm", "", false, "remove the limit template")
+	cmd.Flags().Int64VarP(&params.conns.NumberValue, "conns", "", -1, "set maximum active connections for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.leafConns.NumberValue, "leaf-conns", "", -1, "set maximum active leaf node connections for the account (-1 is unlimited)")
+	cmd.Flags().StringVarP(&params.data.Value, "data", "", "-1", "set maximum data in bytes for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.exports.NumberValue, "exports", "", -1, "set maximum number of exports for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.imports.NumberValue, "imports", "", -1, "set maximum number of imports for the account (-1 is unlimited)")
+	cmd.Flags().StringVarP(&params.payload.Value, "payload", "", "-1", "set maximum message payload in bytes for the account (-1 is unlimited)")
+	cmd.Flags().Int64VarP(&params.subscriptions.NumberValue, "subscriptions", "", -1, "set maximum subscription for the account (-1 is unlimited)")
+
+	return cmd
+}
+
+func init() {
+	editCmd.AddCommand(createEditLimitTemplateCmd())
+}
+
+type EditLimitTemplateParams struct {
+	name          string
+	remove        bool
+	templates     map[string]*store.LimitTemplate
+	conns         NumberParams
+	leafConns     NumberParams
+	exports       NumberParams
+	imports       NumberParams
+	subscriptions NumberParams
+	payload       DataParams
+	data          DataParams
+}
+
+func (p *EditLimitTemplateParams) SetDefaults(ctx ActionCtx) error {
+	p.name = NameFlagOrArgument(p.name, ctx)
+	if p.name == "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("limit template name is required")
+	}
+	if !p.remove && ctx.NothingToDo("conns", "leaf-conns", "exports", "imports", "payload", "data", "subscriptions") {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("specify an edit option")
+	}
+	return nil
+}
+
+func (p *EditLimitTemplateParams) PreInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *EditLimitTemplateParams) Load(ctx ActionCtx) error {
+	var err error
+	p.templates, err = ctx.StoreCtx().Store.ReadLimitTemplates()
+	return err
+}
+
+func (p *EditLimitTemplateParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *EditLimitTemplateParams) Validate(ctx ActionCtx) error {
+	var err error
+	if p.remove {
+		if p.templates[p.name] == nil {
+			return fmt.Errorf("limit template %q not found", p.name)
+		}
+		return nil
+	}
+	if p.data.Number, err = p.data.NumberValue(); err != nil {
+		return fmt.Errorf("error parsing %s: %s", "data", p.data.Value)
+	}
+	if p.payload.Number, err = p.payload.NumberValue(); err != nil {
+		return fmt.Errorf("error parsing %s: %s", "payload", p.payload.Value)
+	}
+	return nil
+}
+
+func (p *EditLimitTemplateParams) Run(ctx ActionCtx) (store.Status, error) {
+	r := store.NewDetailedReport(true)
+	if p.remove {
+		delete(p.templates, p.name)
+		if err := ctx.StoreCtx().Store.WriteLimitTemplates(p.templates); err != nil {
+			return nil, err
+		}
+		r.AddOK("removed limit template %q", p.name)
+		return r, nil
+	}
+
+	t := p.templates[p.name]
+	if t == nil {
+		t = &store.LimitTemplate{Subs: -1, Conn: -1, LeafNodeConn: -1, Imports: -1, Exports: -1, Data: -1, Payload: -1}
+		p.templates[p.name] = t
+	}
+	flags := ctx.CurrentCmd().Flags()
+	if flags.Changed("conns") {
+		t.Conn = p.conns.NumberValue
+		r.AddOK("changed max connections to %d", t.Conn)
+	}
+	if flags.Changed("leaf-conns") {
+		t.LeafNodeConn = p.leafConns.NumberValue
+		r.AddOK("changed leaf node connections to %d", t.LeafNodeConn)
+	}
+	if flags.Changed("data") {
+		t.Data = p.data.Number
+		r.AddOK("changed max data to %d bytes", t.Data)
+	}
+	if flags.Changed("exports") {
+		t.Exports = p.exports.NumberValue
+		r.AddOK("changed max exports to %d", t.Exports)
+	}
+	if flags.Changed("imports") {
+		t.Imports = p.imports.NumberValue
+		r.AddOK("changed max imports to %d", t.Imports)
+	}
+	if flags.Changed("payload") {
+		t.Payload = p.payload.Number
+		r.AddOK("changed max payload to %d bytes", t.Payload)
+	}
+	if flags.Changed("subscriptions") {
+		t.Subs = p.subscriptions.NumberValue
+		r.AddOK("changed max subscriptions to %d", t.Subs)
+	}
+	if err := ctx.StoreCtx().Store.WriteLimitTemplates(p.templates); err != nil {
+		return nil, err
+	}
+	r.AddOK("edited limit template %q", p.name)
+	return r, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EditLimitTemplate(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(createEditLimitTemplateCmd(), "small", "--conns", "10")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createEditLimitTemplateCmd(), "small", "--data", "1M")
+	require.NoError(t, err)
+
+	templates, err := ts.Store.ReadLimitTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	lt := templates["small"]
+	require.NotNil(t, lt)
+	require.Equal(t, int64(10), lt.Conn)
+	require.Equal(t, int64(1000*1000), lt.Data)
+	require.Equal(t, int64(-1), lt.Subs)
+
+	_, _, err = ExecuteCmd(createEditLimitTemplateCmd(), "small", "--rm")
+	require.NoError(t, err)
+	templates, err = ts.Store.ReadLimitTemplates()
+	require.NoError(t, err)
+	require.Empty(t, templates)
+
+	_, _, err = ExecuteCmd(createEditLimitTemplateCmd(), "small", "--rm")
+	require.Error(t, err)
+	_, _, err = ExecuteCmd(createEditLimitTemplateCmd(), "small")
+	require.Error(t, err)
+}
@@ -195,7 +195,18 @@ func (p *EditOperatorParams) Run(ctx ActionCtx) (store.Status, error) {
 	if err = p.GenericClaimsParams.Run(ctx, p.claim, r); err != nil {
 		return nil, err
 	}
-	keys, _ := p.signingKeys.PublicKeys()
+	keys, err := p.signingKeys.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	ks := ctx.StoreCtx().KeyStore
+	skPaths, err := p.signingKeys.StoreGenerated(&ks)
+	if err != nil {
+		return nil, err
+	}
+	for _, fp := range skPaths {
+		r.AddOK("generated and stored operator signing key %q", AbbrevHomePaths(fp))
+	}
 	if len(keys) > 0 {
 		p.claim.SigningKeys.Add(keys...)
 		for _, k := range keys {
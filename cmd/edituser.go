@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nkeys"
@@ -41,9 +42,16 @@ nsc edit user --name <n> --deny-pubsub <subject>,...
 nsc edit user --name <n> --deny-pub <subject>,...
 nsc edit user --name <n> --deny-sub <subject>,...
 
+# To deny everything not explicitly allowed (least-privilege user):
+nsc edit user --name <n> --deny-default --allow-pub <subject>,...
+
 # Remove a previously set permissions
 nsc edit user --name <n> --rm <subject>,...
 
+# Remove a previously set publish or subscribe permission only
+nsc edit user --name <n> --rm-pub <subject>,...
+nsc edit user --name <n> --rm-sub <subject>,...
+
 # To dynamically allow publishing to reply subjects, this works well for service responders:
 nsc edit user --name <n> --allow-pub-response
 
@@ -56,8 +64,30 @@ nsc edit user --name <n> --allow-pub-response=5
 # See 'nsc edit export --response-type --help' to enable multiple
 # responses between accounts.
 
+# To allow a single response or a stream of responses with a TTL:
+nsc edit user --name <n> --response-type singleton --response-ttl 5s
+nsc edit user --name <n> --response-type stream --allow-pub-response=10
+
 # To remove response settings:
 nsc edit user --name <n> --rm-response-perms
+
+# Allow the subjects of exports of the account, referenced by export name:
+nsc edit user --name <n> --allow-sub-from-export <export>,...
+nsc edit user --name <n> --allow-pub-from-export <export>,...
+
+# Add the permissions of another user in the account (the template):
+nsc edit user --name <n> --template <user>
+
+# Reset the permissions to exactly those of the template, anything
+# not in the template is removed:
+nsc edit user --name <n> --template <user> --reconcile
+
+# Add the tags of another user in the account:
+nsc edit user --name <n> --copy-tags-from <user>
+
+# Re-issue the user, the expiry is moved forward keeping the same
+# validity duration:
+nsc edit user --name <n> --renew
 `,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
@@ -67,17 +97,26 @@ nsc edit user --name <n> --rm-response-perms
 	}
 
 	cmd.Flags().StringSliceVarP(&params.remove, "rm", "", nil, "remove publish/subscribe and deny permissions - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.rmPub, "rm-pub", "", nil, "remove publish and deny publish permissions - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.rmSub, "rm-sub", "", nil, "remove subscribe and deny subscribe permissions - comma separated list or option can be specified multiple times")
 
 	cmd.Flags().StringSliceVarP(&params.allowPubs, "allow-pub", "", nil, "add publish permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.allowPubsub, "allow-pubsub", "", nil, "add publish and subscribe permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.allowSubs, "allow-sub", "", nil, "add subscribe permissions - comma separated list or option can be specified multiple times")
 
+	cmd.Flags().StringSliceVarP(&params.allowPubFromExport, "allow-pub-from-export", "", nil, "add publish permissions for the subjects of the named account exports - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&params.allowSubFromExport, "allow-sub-from-export", "", nil, "add subscribe permissions for the subjects of the named account exports - comma separated list or option can be specified multiple times")
+
 	cmd.Flags().StringSliceVarP(&params.denyPubs, "deny-pub", "", nil, "add deny publish permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.denyPubsub, "deny-pubsub", "", nil, "add deny publish and subscribe permissions - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.denySubs, "deny-sub", "", nil, "add deny subscribe permissions - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.denyPubsFile, "pub-deny-from-file", "", "", "add deny publish permissions read from a file with a subject per line")
+	cmd.Flags().StringVarP(&params.denySubsFile, "sub-deny-from-file", "", "", "add deny subscribe permissions read from a file with a subject per line")
+	cmd.Flags().BoolVarP(&params.denyDefault, "deny-default", "", false, "deny publish and subscribe on all subjects not explicitly allowed")
 
 	cmd.Flags().StringSliceVarP(&params.tags, "tag", "", nil, "add tags for user - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.rmTags, "rm-tag", "", nil, "remove tag - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringVarP(&params.tagExpiry, "tag-expiry", "", "", "review date for the user (yyyy-mm-dd), replaces any review:<date> tag")
 
 	cmd.Flags().StringSliceVarP(&params.src, "source-network", "", nil, "add source network for connection - comma separated list or option can be specified multiple times")
 	cmd.Flags().StringSliceVarP(&params.rmSrc, "rm-source-network", "", nil, "remove source network for connection - comma separated list or option can be specified multiple times")
@@ -85,10 +124,15 @@ nsc edit user --name <n> --rm-response-perms
 	cmd.Flags().Int64VarP(&params.payload.Number, "payload", "", -1, "set maximum message payload in bytes for the account (-1 is unlimited)")
 
 	cmd.Flags().StringVarP(&params.name, "name", "n", "", "user name")
+	cmd.Flags().StringVarP(&params.template, "template", "", "", "name of a user in the account whose permissions are applied to the user")
+	cmd.Flags().BoolVarP(&params.reconcile, "reconcile", "", false, "replace the permissions with the ones in the template (requires --template)")
+	cmd.Flags().StringVarP(&params.copyTagsFrom, "copy-tags-from", "", "", "name of a user in the account whose tags are added to the user")
+	cmd.Flags().BoolVarP(&params.renew, "renew", "", false, "re-issue the user moving the expiry forward by the time since it was issued")
 
 	params.AccountContextParams.BindFlags(cmd)
 	params.GenericClaimsParams.BindFlags(cmd)
 	params.ResponsePermsParams.bindSetFlags(cmd)
+	params.ResponsePermsParams.bindTypeFlag(cmd)
 	params.ResponsePermsParams.bindRemoveFlags(cmd)
 
 	return cmd
@@ -107,17 +151,33 @@ type EditUserParams struct {
 	name          string
 	token         string
 	credsFilePath string
+	template      string
+	reconcile     bool
+	templateClaim *jwt.UserClaims
+	renew         bool
+	copyTagsFrom  string
+	copiedTags    []string
 
 	allowPubs   []string
 	allowPubsub []string
 	allowSubs   []string
 	denyPubs    []string
+
+	allowPubFromExport []string
+	allowSubFromExport []string
+	denyPubsFile       string
+	denySubsFile       string
+
 	denyPubsub  []string
 	denySubs    []string
+	denyDefault bool
 	remove      []string
+	rmPub       []string
+	rmSub       []string
 	rmSrc       []string
 	src         []string
 	payload     DataParams
+	tagExpiry   string
 }
 
 func (p *EditUserParams) SetDefaults(ctx ActionCtx) error {
@@ -125,12 +185,22 @@ func (p *EditUserParams) SetDefaults(ctx ActionCtx) error {
 	p.AccountContextParams.SetDefaults(ctx)
 	p.SignerParams.SetDefaults(nkeys.PrefixByteAccount, true, ctx)
 
-	if !InteractiveFlag && ctx.NothingToDo("start", "expiry", "rm", "allow-pub", "allow-sub", "allow-pubsub",
-		"deny-pub", "deny-sub", "deny-pubsub", "tag", "rm-tag", "source-network", "rm-source-network", "payload",
-		"rm-response-perms", "max-responses", "response-ttl", "allow-pub-response") {
+	if !InteractiveFlag && ctx.NothingToDo("start", "expiry", "rm", "rm-pub", "rm-sub", "allow-pub", "allow-sub", "allow-pubsub",
+		"allow-pub-from-export", "allow-sub-from-export", "deny-pub",
+		"pub-deny-from-file", "sub-deny-from-file", "deny-sub", "deny-pubsub", "tag", "rm-tag", "tag-expiry", "source-network", "rm-source-network", "payload",
+		"rm-response-perms", "max-responses", "response-ttl", "allow-pub-response", "response-type", "template", "deny-default", "renew", "copy-tags-from") {
 		ctx.CurrentCmd().SilenceUsage = false
 		return fmt.Errorf("specify an edit option")
 	}
+
+	// the time flags default to "0" - only change the dates if requested
+	flags := ctx.CurrentCmd().Flags()
+	if !flags.Changed("start") {
+		p.Start = ""
+	}
+	if !flags.Changed("expiry") {
+		p.Expiry = ""
+	}
 	return nil
 }
 
@@ -178,6 +248,38 @@ func (p *EditUserParams) Load(ctx ActionCtx) error {
 		return err
 	}
 
+	if p.template != "" {
+		if !ctx.StoreCtx().Store.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.template)) {
+			return fmt.Errorf("template user %q not found", p.template)
+		}
+		p.templateClaim, err = ctx.StoreCtx().Store.ReadUserClaim(p.AccountContextParams.Name, p.template)
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.copyTagsFrom != "" {
+		if !ctx.StoreCtx().Store.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.copyTagsFrom)) {
+			return fmt.Errorf("user %q to copy tags from not found", p.copyTagsFrom)
+		}
+		uc, err := ctx.StoreCtx().Store.ReadUserClaim(p.AccountContextParams.Name, p.copyTagsFrom)
+		if err != nil {
+			return err
+		}
+		// the review date belongs to the source user
+		for _, t := range uc.Tags {
+			if !strings.HasPrefix(t, reviewTagPrefix) {
+				p.copiedTags = append(p.copiedTags, t)
+			}
+		}
+	}
+
+	if len(p.allowPubFromExport) > 0 || len(p.allowSubFromExport) > 0 {
+		if err = p.loadExportSubjects(ctx); err != nil {
+			return err
+		}
+	}
+
 	if !ctx.CurrentCmd().Flag("payload").Changed {
 		p.payload.Number = p.claim.Limits.Payload
 	}
@@ -185,6 +287,42 @@ func (p *EditUserParams) Load(ctx ActionCtx) error {
 	return err
 }
 
+// loadExportSubjects adds the subjects of the exports named by
+// --allow-pub-from-export and --allow-sub-from-export to the permissions
+func (p *EditUserParams) loadExportSubjects(ctx ActionCtx) error {
+	ac, err := ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	subjects := func(names []string) ([]string, error) {
+		var subjects []string
+		for _, n := range names {
+			found := false
+			for _, e := range ac.Exports {
+				if e.Name == n {
+					subjects = append(subjects, string(e.Subject))
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("export %q not found in account %q", n, p.AccountContextParams.Name)
+			}
+		}
+		return subjects, nil
+	}
+	pubs, err := subjects(p.allowPubFromExport)
+	if err != nil {
+		return err
+	}
+	p.allowPubs = append(p.allowPubs, pubs...)
+	subs, err := subjects(p.allowSubFromExport)
+	if err != nil {
+		return err
+	}
+	p.allowSubs = append(p.allowSubs, subs...)
+	return nil
+}
+
 func (p *EditUserParams) PostInteractive(ctx ActionCtx) error {
 	// FIXME: we won't do interactive on the response params until pub/sub/deny permissions are interactive
 	//if err := p.ResponsePermsParams.Edit(p.claim.Resp != nil); err != nil {
@@ -215,6 +353,16 @@ func (p *EditUserParams) Validate(ctx ActionCtx) error {
 	if err != nil {
 		return fmt.Errorf("error parsing %s: %s", "payload", p.payload.Value)
 	}
+	if p.reconcile && p.template == "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("--reconcile requires --template")
+	}
+	if p.template != "" && p.template == p.name {
+		return fmt.Errorf("user %q cannot be its own template", p.name)
+	}
+	if p.renew && p.IsExpiryChanged() {
+		return fmt.Errorf("specify only one of --renew or --expiry")
+	}
 	if err = p.GenericClaimsParams.Valid(); err != nil {
 		return err
 	}
@@ -229,15 +377,90 @@ func (p *EditUserParams) Validate(ctx ActionCtx) error {
 		return err
 	}
 
+	if p.src, err = normalizeSourceNetworks(p.src); err != nil {
+		return err
+	}
+	if _, err = normalizeSourceNetworks(p.rmSrc); err != nil {
+		return err
+	}
+
+	if err = loadSubjectsFile(p.denyPubsFile, &p.denyPubs); err != nil {
+		return err
+	}
+	if err = loadSubjectsFile(p.denySubsFile, &p.denySubs); err != nil {
+		return err
+	}
+
+	if p.tagExpiry != "" {
+		if p.tagExpiry, err = reviewTag(p.tagExpiry); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// applyTemplate adds the template's permissions to the user, when reconciling
+// the template's permissions replace the user's permissions
+func (p *EditUserParams) applyTemplate(r *store.Report) {
+	if p.templateClaim == nil {
+		return
+	}
+	tp := p.templateClaim.Permissions
+	if p.reconcile {
+		p.claim.Permissions = jwt.Permissions{}
+	}
+	p.claim.Pub.Allow.Add(tp.Pub.Allow...)
+	p.claim.Pub.Deny.Add(tp.Pub.Deny...)
+	p.claim.Sub.Allow.Add(tp.Sub.Allow...)
+	p.claim.Sub.Deny.Add(tp.Sub.Deny...)
+	if tp.Resp != nil {
+		resp := *tp.Resp
+		p.claim.Resp = &resp
+	}
+	if p.reconcile {
+		r.AddOK("reconciled permissions with template %q", p.template)
+	} else {
+		r.AddOK("added permissions from template %q", p.template)
+	}
+}
+
+// renewExpiry moves the expiry forward keeping the duration between issue and
+// expiry, the new issue time and id are set when the claim is encoded
+func (p *EditUserParams) renewExpiry(r *store.Report) {
+	if p.claim.Expires > 0 && p.claim.IssuedAt > 0 {
+		d := p.claim.Expires - p.claim.IssuedAt
+		p.claim.Expires = time.Now().Unix() + d
+		r.AddOK("renewed user expiry to %s - %s", UnixToDate(p.claim.Expires), strings.ToLower(HumanizedDate(p.claim.Expires)))
+		return
+	}
+	r.AddOK("renewed user")
+}
+
 func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	r := store.NewDetailedReport(true)
 	r.ReportSum = false
 
 	var err error
 	p.GenericClaimsParams.Run(ctx, p.claim, r)
+	if p.tagExpiry != "" {
+		setReviewTag(&p.claim.Tags, p.tagExpiry)
+		r.AddOK("set review tag %q", p.tagExpiry)
+	}
+
+	p.applyTemplate(r)
+
+	for _, t := range p.copiedTags {
+		if !p.claim.Tags.Contains(t) {
+			p.claim.Tags.Add(t)
+			r.AddOK("copied tag %q from user %q", strings.ToLower(t), p.copyTagsFrom)
+		}
+	}
+	sort.Strings(p.claim.Tags)
+
+	if p.renew {
+		p.renewExpiry(r)
+	}
 
 	var ap []string
 	p.claim.Permissions.Pub.Allow.Add(p.allowPubs...)
@@ -247,8 +470,10 @@ func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	for _, v := range ap {
 		r.AddOK("added pub pub %q", v)
 	}
-	p.claim.Permissions.Pub.Allow.Remove(p.remove...)
-	for _, v := range p.remove {
+	var rmPub []string
+	rmPub = append(rmPub, p.remove...)
+	rmPub = append(rmPub, p.rmPub...)
+	for _, v := range removeSubjects(&p.claim.Permissions.Pub.Allow, rmPub) {
 		r.AddOK("removed pub %q", v)
 	}
 	sort.Strings(p.claim.Pub.Allow)
@@ -261,8 +486,7 @@ func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	for _, v := range dp {
 		r.AddOK("added deny pub %q", v)
 	}
-	p.claim.Permissions.Pub.Deny.Remove(p.remove...)
-	for _, v := range p.remove {
+	for _, v := range removeSubjects(&p.claim.Permissions.Pub.Deny, rmPub) {
 		r.AddOK("removed deny pub %q", v)
 	}
 	sort.Strings(p.claim.Permissions.Pub.Deny)
@@ -275,17 +499,26 @@ func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	for _, v := range sa {
 		r.AddOK("added sub %q", v)
 	}
-	p.claim.Permissions.Sub.Allow.Remove(p.remove...)
-	for _, v := range p.remove {
+	var rmSub []string
+	rmSub = append(rmSub, p.remove...)
+	rmSub = append(rmSub, p.rmSub...)
+	for _, v := range removeSubjects(&p.claim.Permissions.Sub.Allow, rmSub) {
 		r.AddOK("removed sub %q", v)
 	}
 	sort.Strings(p.claim.Permissions.Sub.Allow)
 
 	p.claim.Permissions.Sub.Deny.Add(p.denySubs...)
 	p.claim.Permissions.Sub.Deny.Add(p.denyPubsub...)
-	p.claim.Permissions.Sub.Deny.Remove(p.remove...)
+	for _, v := range removeSubjects(&p.claim.Permissions.Sub.Deny, rmSub) {
+		r.AddOK("removed deny sub %q", v)
+	}
 	sort.Strings(p.claim.Permissions.Sub.Deny)
 
+	if p.denyDefault {
+		denyByDefault(&p.claim.Permissions)
+		r.AddOK("denied subjects not explicitly allowed")
+	}
+
 	flags := ctx.CurrentCmd().Flags()
 	p.claim.Limits.Payload = p.payload.Number
 	if flags.Changed("payload") {
@@ -299,10 +532,13 @@ func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	for _, v := range p.src {
 		r.AddOK("added src network %s", v)
 	}
-	srcList.Remove(p.rmSrc...)
-	for _, v := range p.rmSrc {
+	removed := removeSourceNetworks(&srcList, p.rmSrc)
+	for _, v := range removed {
 		r.AddOK("removed src network %s", v)
 	}
+	if len(p.rmSrc) > 0 && len(removed) == 0 {
+		r.AddWarning("no src networks were removed - none matched %s", strings.Join(p.rmSrc, ","))
+	}
 	sort.Strings(srcList)
 	p.claim.Src = strings.Join(srcList, ",")
 
@@ -372,3 +608,16 @@ func (p *EditUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	}
 	return r, nil
 }
+
+// removeSubjects removes the subjects from the list and returns the ones
+// that were in it
+func removeSubjects(list *jwt.StringList, rm []string) []string {
+	var removed []string
+	for _, v := range rm {
+		if list.Contains(v) {
+			list.Remove(v)
+			removed = append(removed, v)
+		}
+	}
+	return removed
+}
@@ -16,6 +16,8 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -197,6 +199,39 @@ func Test_EditUser_Src(t *testing.T) {
 	require.ElementsMatch(t, strings.Split(cc.Src, ","), []string{"192.0.1.0/8"})
 }
 
+func Test_EditUserRmLegacySrc(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddUser(t, "A", "U")
+
+	// users created before source networks were normalized keep bare IPs
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	uc.Src = "1.2.3.4,10.0.0.0/8"
+	akp, err := ts.KeyStore.GetKeyPair(ts.GetAccountPublicKey(t, "A"))
+	require.NoError(t, err)
+	token, err := uc.Encode(akp)
+	require.NoError(t, err)
+	_, err = ts.Store.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "--rm-source-network", "1.2.3.4")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "removed src network 1.2.3.4")
+	require.NotContains(t, stderr, "1.2.3.4/32")
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/8", uc.Src)
+
+	_, stderr, err = ExecuteCmd(createEditUserCmd(), "--rm-source-network", "5.6.7.8")
+	require.NoError(t, err)
+	require.NotContains(t, stderr, "removed src network")
+	require.Contains(t, stderr, "no src networks were removed")
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/8", uc.Src)
+}
+
 func Test_EditUserSK(t *testing.T) {
 	ts := NewTestStore(t, "O")
 	t.Log(ts.Dir)
@@ -331,3 +366,388 @@ func Test_EditUserResponsePermissions2(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, uc.Resp)
 }
+
+func Test_EditUserTemplateReconcile(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "T", "--allow-pub", "foo", "--allow-sub", "bar")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pubsub", ">", "--deny-pubsub", "baz", "--allow-pub-response")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--template", "T", "--reconcile")
+	require.NoError(t, err)
+
+	tc, err := ts.Store.ReadUserClaim("A", "T")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, tc.Permissions, uc.Permissions)
+	require.ElementsMatch(t, []string{"foo"}, uc.Pub.Allow)
+	require.ElementsMatch(t, []string{"bar"}, uc.Sub.Allow)
+	require.Empty(t, uc.Pub.Deny)
+	require.Empty(t, uc.Sub.Deny)
+	require.Nil(t, uc.Resp)
+}
+
+func Test_EditUserTemplateMerge(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "T", "--allow-pub", "foo")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub", "bar")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--template", "T")
+	require.NoError(t, err)
+
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"foo", "bar"}, uc.Pub.Allow)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--template", "X")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template user \"X\" not found")
+}
+
+func Test_EditUserDenyDefault(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "--deny-default")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Pub.Deny, []string{">"})
+	require.ElementsMatch(t, uc.Sub.Deny, []string{">"})
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "--deny-default", "--allow-pub", "foo")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Pub.Allow, []string{"foo"})
+	require.Empty(t, uc.Pub.Deny)
+	require.ElementsMatch(t, uc.Sub.Deny, []string{">"})
+}
+
+func Test_EditUserResponsePermsValidation(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "--max-responses", "-1", "--response-ttl", "-1s")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max responses must be non-negative")
+	require.Contains(t, err.Error(), "response ttl cannot be negative")
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "--allow-pub-response=-2", "--response-ttl", "xx")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max responses must be non-negative")
+	require.Contains(t, err.Error(), `response ttl "xx" is invalid`)
+}
+
+func Test_EditUserResponseTTLClear(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub-response=10", "--response-ttl", "5s")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, 10, uc.Resp.MaxMsgs)
+	require.Equal(t, 5*time.Second, uc.Resp.Expires)
+
+	// tune the ttl on the existing user
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-ttl", "1m")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, 10, uc.Resp.MaxMsgs)
+	require.Equal(t, time.Minute, uc.Resp.Expires)
+
+	// zero clears the ttl and keeps the max
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-ttl", "0s")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, 10, uc.Resp.MaxMsgs)
+	require.Zero(t, uc.Resp.Expires)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--rm-response-perms")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Nil(t, uc.Resp)
+}
+
+func Test_EditUserAllowPubResponseZero(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub-response=10", "--response-ttl", "5s")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "U", "--allow-pub-response=0")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "set max responses to 0 - kept the response permission")
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, 0, uc.Resp.MaxMsgs)
+	require.Equal(t, 5*time.Second, uc.Resp.Expires)
+}
+
+func Test_EditUserRmResponsePermsFromExisting(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub-response=10", "--response-ttl", "5s")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "U", "--rm-response-perms")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "removed response permissions - the max responses and ttl are gone")
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Nil(t, uc.Resp)
+}
+
+func Test_EditUserResponseTTLDays(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "U", "--allow-pub-response", "--response-ttl", "1d")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, 24*time.Hour, uc.Resp.Expires)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-ttl", "1w")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `response ttl "1w" is invalid`)
+}
+
+func Test_EditUserRenew(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--expiry", "2h", "--allow-pub", "foo")
+	require.NoError(t, err)
+	before, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.True(t, before.Expires > 0)
+
+	// issue times have second resolution
+	time.Sleep(1100 * time.Millisecond)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--renew")
+	require.NoError(t, err)
+	after, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+
+	require.True(t, after.IssuedAt > before.IssuedAt)
+	require.True(t, after.Expires > before.Expires)
+	// the expiry and the issue time are separate clock reads that can
+	// straddle a second boundary
+	require.InDelta(t, before.Expires-before.IssuedAt, after.Expires-after.IssuedAt, 1)
+	require.NotEqual(t, before.ID, after.ID)
+	require.ElementsMatch(t, before.Pub.Allow, after.Pub.Allow)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--renew", "--expiry", "1d")
+	require.Error(t, err)
+}
+
+func Test_EditUserKeepsExpiry(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--expiry", "2h")
+	require.NoError(t, err)
+	before, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--tag", "a")
+	require.NoError(t, err)
+	after, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, before.Expires, after.Expires)
+}
+
+func Test_EditUserRemovePubSub(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--allow-pub", "bar", "--deny-pubsub", "foo,baz")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "U", "--rm-pub", "foo", "--rm-pub", "nope")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `removed deny pub "foo"`)
+	require.NotContains(t, stderr, `removed pub "foo"`)
+	require.NotContains(t, stderr, `"nope"`)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Pub.Allow, []string{"bar"})
+	require.ElementsMatch(t, uc.Pub.Deny, []string{"baz"})
+	require.ElementsMatch(t, uc.Sub.Deny, []string{"baz", "foo"})
+
+	_, stderr, err = ExecuteCmd(createEditUserCmd(), "U", "--rm-sub", "baz", "--rm-sub", "nope")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `removed deny sub "baz"`)
+	require.NotContains(t, stderr, `removed sub "baz"`)
+	require.NotContains(t, stderr, `"nope"`)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Pub.Deny, []string{"baz"})
+	require.ElementsMatch(t, uc.Sub.Deny, []string{"foo"})
+}
+
+func Test_EditUserRemoveTagLast(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "U", "--tag", "foo,bar", "--rm-tag", "Foo")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `removed tag "foo"`)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Tags, []string{"bar"})
+}
+
+func Test_EditUserResponseTypeSingleton(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "U", "--response-type", "singleton", "--response-ttl", "5s")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, 1, uc.Resp.MaxMsgs)
+	require.Equal(t, 5*time.Second, uc.Resp.Expires)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-type", "singleton", "--allow-pub-response=3")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "singleton responses allow a single message")
+}
+
+func Test_EditUserResponseTypeStream(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "U", "--response-type", "stream", "--response-ttl", "1m")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.NotNil(t, uc.Resp)
+	require.Equal(t, -1, uc.Resp.MaxMsgs)
+	require.Equal(t, time.Minute, uc.Resp.Expires)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-type", "stream", "--allow-pub-response=10")
+	require.NoError(t, err)
+	uc, err = ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, 10, uc.Resp.MaxMsgs)
+	require.Equal(t, time.Minute, uc.Resp.Expires)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--response-type", "chunked")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `response type "chunked" is invalid`)
+}
+
+func Test_EditUserAllowFromExport(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createAddExportCmd(), "--service", "--subject", "help.>", "--name", "help")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createAddExportCmd(), "--subject", "events.>", "--name", "events")
+	require.NoError(t, err)
+	ts.AddUser(t, "A", "U")
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--allow-sub-from-export", "help", "--allow-pub-from-export", "events")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Permissions.Sub.Allow, jwt.StringList{"help.>"})
+	require.ElementsMatch(t, uc.Permissions.Pub.Allow, jwt.StringList{"events.>"})
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--allow-sub-from-export", "nope")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `export "nope" not found`)
+}
+
+func Test_EditUserDenyFromFile(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	pubs := filepath.Join(ts.Dir, "pub-deny.txt")
+	require.NoError(t, ioutil.WriteFile(pubs, []byte("# internal subjects\ninternal.a\n\n  internal.b  \ninternal.a\n"), 0600))
+	subs := filepath.Join(ts.Dir, "sub-deny.txt")
+	require.NoError(t, ioutil.WriteFile(subs, []byte("secret.>\n"), 0600))
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "U", "--pub-deny-from-file", pubs, "--sub-deny-from-file", subs)
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.ElementsMatch(t, uc.Permissions.Pub.Deny, []string{"internal.a", "internal.b"})
+	require.ElementsMatch(t, uc.Permissions.Sub.Deny, []string{"secret.>"})
+
+	bad := filepath.Join(ts.Dir, "bad.txt")
+	require.NoError(t, ioutil.WriteFile(bad, []byte("ok\nnot ok\n"), 0600))
+	_, _, err = ExecuteCmd(createEditUserCmd(), "U", "--pub-deny-from-file", bad)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2")
+}
+
+func Test_EditUserCopyTagsFrom(t *testing.T) {
+	ts := NewTestStore(t, "edit user")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "src")
+	ts.AddUser(t, "A", "dst")
+
+	_, _, err := ExecuteCmd(createEditUserCmd(), "--name", "src", "--tag", "Team-A,prod", "--tag-expiry", "2030-01-01")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createEditUserCmd(), "--name", "dst", "--tag", "prod")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createEditUserCmd(), "--name", "dst", "--copy-tags-from", "src")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `copied tag "team-a" from user "src"`)
+	require.NotContains(t, stderr, `copied tag "prod"`)
+
+	uc, err := ts.Store.ReadUserClaim("A", "dst")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"prod", "team-a"}, uc.Tags)
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "--name", "dst", "--copy-tags-from", "nobody")
+	require.Error(t, err)
+}
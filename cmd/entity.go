@@ -138,6 +138,19 @@ func (c *Entity) GenerateClaim(signer nkeys.KeyPair, ctx ActionCtx) (store.Statu
 	if !c.create {
 		return nil, nil
 	}
+	token, err := c.EncodeClaim(signer, ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := GetStore()
+	if err != nil {
+		return nil, err
+	}
+	return s.StoreClaim([]byte(token))
+}
+
+// EncodeClaim returns the signed jwt for the entity without storing it
+func (c *Entity) EncodeClaim(signer nkeys.KeyPair, ctx ActionCtx) (string, error) {
 	// self-sign if we don't have a parent keypair
 	if signer == nil {
 		signer = c.kp
@@ -145,12 +158,12 @@ func (c *Entity) GenerateClaim(signer nkeys.KeyPair, ctx ActionCtx) (store.Statu
 
 	pub, err := c.kp.PublicKey()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	s, err := GetStore()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	var claim jwt.Claims
@@ -168,11 +181,11 @@ func (c *Entity) GenerateClaim(signer nkeys.KeyPair, ctx ActionCtx) (store.Statu
 		claim = uc
 		ctx, err := s.GetContext()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		spk, err := signer.PublicKey()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		if ctx.Account.PublicKey != spk {
 			uc.IssuerAccount = ctx.Account.PublicKey
@@ -187,16 +200,11 @@ func (c *Entity) GenerateClaim(signer nkeys.KeyPair, ctx ActionCtx) (store.Statu
 
 	if c.editFn != nil {
 		if err = c.editFn(claim, ctx); err != nil {
-			return nil, err
+			return "", err
 		}
 	}
 
-	token, err := claim.Encode(signer)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.StoreClaim([]byte(token))
+	return claim.Encode(signer)
 }
 
 func (c *Entity) ValidateNKey() cli.Validator {
@@ -18,6 +18,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/spf13/cobra"
@@ -44,7 +45,7 @@ func createEnvCmd() *cobra.Command {
 		Args:          MaxArgs(0),
 		SilenceErrors: false,
 		SilenceUsage:  false,
-		Example:       "env",
+		Example:       "env\nenv --operator <name> --account <name>",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := params.Run(cmd); err != nil {
 				return err
@@ -99,6 +100,9 @@ func (p *SetContextParams) PrintEnv(cmd *cobra.Command) {
 	}
 	table.AddRow("From CWD", "", yn(GetCwdCtx() != nil))
 	table.AddRow("Stores Dir", "", AbbrevHomePaths(r))
+	if conf.StoreRoot != "" && conf.Operator != "" {
+		table.AddRow("Current Store Dir", "", AbbrevHomePaths(filepath.Join(conf.StoreRoot, conf.Operator)))
+	}
 	table.AddRow("Default Operator", "", conf.Operator)
 	table.AddRow("Default Account", "", conf.Account)
 	cmd.Println(table.Render())
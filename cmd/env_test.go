@@ -54,6 +54,32 @@ func TestEnv_SetAccountOutput(t *testing.T) {
 	require.Contains(t, stderr, "Default Account B")
 }
 
+func TestEnv_SwitchAccountContext(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddAccount(t, "B")
+
+	_, stderr, err := ExecuteCmd(createEnvCmd(), "--account", "A")
+	require.NoError(t, err)
+	stderr = StripTableDecorations(stderr)
+	require.Contains(t, stderr, fmt.Sprintf("Current Store Dir %s", ts.Store.Dir))
+	require.Contains(t, stderr, "Default Account A")
+
+	// commands without --account use the switched context
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U")
+	require.NoError(t, err)
+	require.True(t, ts.Store.Has(store.Accounts, "A", store.Users, store.JwtName("U")))
+	require.False(t, ts.Store.Has(store.Accounts, "B", store.Users, store.JwtName("U")))
+
+	_, _, err = ExecuteCmd(createEnvCmd(), "--account", "B")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V")
+	require.NoError(t, err)
+	require.True(t, ts.Store.Has(store.Accounts, "B", store.Users, store.JwtName("V")))
+}
+
 func TestEnv_FailsBadOperator(t *testing.T) {
 	ts := NewTestStore(t, "O")
 	defer ts.Done(t)
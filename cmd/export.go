@@ -24,7 +24,7 @@ var exportCmd = &cobra.Command{
 
 var importCmd = &cobra.Command{
 	Use:   "import",
-	Short: "Import assets such as nkeys",
+	Short: "Import assets such as nkeys and accounts",
 }
 
 func init() {
@@ -16,14 +16,23 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/scrypt"
 )
 
 func createExportKeysCmd() *cobra.Command {
@@ -47,6 +56,10 @@ accounts and users. These keys may be referenced in a different  operator contex
 The --filter flag allows you to specify a few letters in a public key and export only 
 those keys that matching the filter (provided the key type matches --operator, --account,
 --user (or --all).
+
+The --output-file flag writes the keys to a gzipped tar archive instead of a directory.
+The archive is encrypted with the passphrase in the environment variable named by
+--passphrase-env using AES-256-GCM and a scrypt derived key.
 `,
 		Example: `nsc export keys --dir <path> (exports the current operator, account and users keys)
 nsc export keys --operator --accounts --users (exports current operators, all accounts, and users)
@@ -54,6 +67,7 @@ nsc export keys --all (same as specifying --operator --accounts --users)
 nsc export keys --operator --not-referenced (exports any other operator keys in the keystore)
 nsc export keys --all --filter VSVMGA (exports all keys containing the filter)
 nsc export keys --account <name> (changes the account context to the specified account)
+nsc export keys --all --output-file keys.tgz --passphrase-env BACKUP_PASSPHRASE (exports all keys to an encrypted archive)
 `,
 		Args:         MaxArgs(0),
 		SilenceUsage: false,
@@ -73,9 +87,10 @@ nsc export keys --account <name> (changes the account context to the specified a
 	cmd.Flags().StringVarP(&params.Filter, "filter", "f", "", "export keys containing string")
 	cmd.Flags().BoolVarP(&params.Unreferenced, "not-referenced", "", false, "export keys that are not referenced in the current operator context")
 	cmd.Flags().StringVarP(&params.Dir, "dir", "d", "", "directory to export keys to")
+	cmd.Flags().StringVarP(&params.OutputFile, "output-file", "", "", "gzipped tar archive to export keys to (exclusive of --dir)")
+	cmd.Flags().StringVarP(&params.PassphraseEnv, "passphrase-env", "", "", "name of the environment variable holding the passphrase used to encrypt the archive (requires --output-file)")
 	cmd.Flags().BoolVarP(&params.Force, "force", "F", false, "overwrite existing files")
 	cmd.Flags().BoolVarP(&params.Remove, "remove", "R", false, "removes the original key file from the keyring after exporting it")
-	cmd.MarkFlagRequired("dir")
 
 	return cmd
 }
@@ -85,9 +100,12 @@ func init() {
 }
 
 type ExportKeysParams struct {
-	Force  bool
-	Remove bool
-	Dir    string
+	Force         bool
+	Remove        bool
+	Dir           string
+	OutputFile    string
+	PassphraseEnv string
+	passphrase    string
 	KeyCollectorParams
 }
 
@@ -108,6 +126,19 @@ func (p *ExportKeysParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *ExportKeysParams) Validate(ctx ActionCtx) error {
+	if (p.Dir == "") == (p.OutputFile == "") {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("specify one of --dir or --output-file")
+	}
+	if p.PassphraseEnv != "" {
+		if p.OutputFile == "" {
+			return errors.New("--passphrase-env requires --output-file")
+		}
+		p.passphrase = os.Getenv(p.PassphraseEnv)
+		if p.passphrase == "" {
+			return fmt.Errorf("environment variable %q is not set", p.PassphraseEnv)
+		}
+	}
 	d := store.GetKeysDir()
 	_, err := os.Stat(d)
 	if os.IsNotExist(err) {
@@ -140,6 +171,13 @@ func (p *ExportKeysParams) Run(ctx ActionCtx) (store.Status, error) {
 				sr.AddError("error reading seed for %s", k.Pub)
 				continue
 			}
+			if p.OutputFile != "" {
+				// the name of the key in the archive
+				j.filepath = fmt.Sprintf("%s.nk", k.Pub)
+				j.data = []byte(s)
+				wj = append(wj, j)
+				continue
+			}
 			j.filepath = filepath.Join(p.Dir, fmt.Sprintf("%s.nk", k.Pub))
 			_, err = os.Stat(j.filepath)
 			if os.IsNotExist(err) || (err == nil && p.Force) {
@@ -156,6 +194,10 @@ func (p *ExportKeysParams) Run(ctx ActionCtx) (store.Status, error) {
 		return nil, errors.New("no keys found to export")
 	}
 
+	if p.OutputFile != "" {
+		return p.writeArchive(ks, wj, sr)
+	}
+
 	if err := MaybeMakeDir(p.Dir); err != nil {
 		return nil, err
 	}
@@ -185,6 +227,136 @@ func (p *ExportKeysParams) Run(ctx ActionCtx) (store.Status, error) {
 	return sr, err
 }
 
+// writeArchive writes the keys with a seed to a gzipped tar archive, keys
+// without a seed are skipped
+func (p *ExportKeysParams) writeArchive(ks store.KeyStore, wj []ExportJob, sr *store.Report) (store.Status, error) {
+	fp, err := Expand(p.OutputFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(fp); err == nil && !p.Force {
+		return nil, fmt.Errorf("%q already exists - specify --force to overwrite", fp)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	var exported []string
+	for _, j := range wj {
+		if j.filepath == "" {
+			sr.AddWarning("skipped %q - no seed available", j.description)
+			continue
+		}
+		hdr := &tar.Header{Name: j.filepath, Mode: 0600, Size: int64(len(j.data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(j.data); err != nil {
+			return nil, err
+		}
+		exported = append(exported, j.description)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	if p.passphrase != "" {
+		if data, err = encryptKeysArchive(data, p.passphrase); err != nil {
+			return nil, err
+		}
+	}
+	if err := ioutil.WriteFile(fp, data, 0600); err != nil {
+		return nil, fmt.Errorf("error writing %q: %v", fp, err)
+	}
+
+	for _, pk := range exported {
+		if p.Remove {
+			if err := ks.Remove(pk); err != nil {
+				sr.AddError("exported %q but failed to delete original file: %v", pk, err)
+				continue
+			}
+			sr.AddOK("moved %q", pk)
+			continue
+		}
+		sr.AddOK("exported %q", pk)
+	}
+	if p.passphrase != "" {
+		sr.AddOK("wrote encrypted keys archive %q", AbbrevHomePaths(fp))
+	} else {
+		sr.AddOK("wrote keys archive %q", AbbrevHomePaths(fp))
+	}
+	return sr, nil
+}
+
+// keysArchiveMagic prefixes encrypted archives, it is followed by the
+// scrypt salt, the gcm nonce and the sealed archive
+const keysArchiveMagic = "NSCKEYS1"
+
+func keysArchiveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+func encryptKeysArchive(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	key, err := keysArchiveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(keysArchiveMagic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, []byte(keysArchiveMagic)), nil
+}
+
+func decryptKeysArchive(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(keysArchiveMagic)) {
+		return nil, errors.New("the archive is not encrypted")
+	}
+	data = data[len(keysArchiveMagic):]
+	if len(data) < 16 {
+		return nil, errors.New("the encrypted archive is truncated")
+	}
+	key, err := keysArchiveKey(passphrase, data[:16])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	data = data[16:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("the encrypted archive is truncated")
+	}
+	d, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(keysArchiveMagic))
+	if err != nil {
+		return nil, errors.New("unable to decrypt the archive - check the passphrase")
+	}
+	return d, nil
+}
+
 type ExportJob struct {
 	description string
 	filepath    string
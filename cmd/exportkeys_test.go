@@ -16,6 +16,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -187,3 +188,77 @@ func Test_ExportNoKeyStore(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "does not exist")
 }
+
+func archiveSeeds(t *testing.T, data []byte) map[string]string {
+	entries, err := readKeysArchive(data)
+	require.NoError(t, err)
+	seeds := make(map[string]string)
+	for _, e := range entries {
+		seeds[e.name] = string(e.seed)
+	}
+	return seeds
+}
+
+func Test_ExportArchive(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	apk := ts.GetAccountPublicKey(t, "A")
+	upk := ts.GetUserPublicKey(t, "A", "U")
+
+	// an account without a stored seed is skipped
+	_, bpk, _ := CreateAccountKey(t)
+	_, _, err := ExecuteCmd(CreateAddAccountCmd(), "--name", "B", "--public-key", bpk)
+	require.NoError(t, err)
+
+	fp := filepath.Join(ts.Dir, "keys.tgz")
+	_, stderr, err := ExecuteCmd(createExportKeysCmd(), "--accounts", "--users", "--account", "A", "--output-file", fp)
+	require.NoError(t, err)
+	require.Contains(t, stderr, "wrote keys archive")
+
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	entries := archiveSeeds(t, d)
+	seed, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+	require.Equal(t, seed, entries[apk+".nk"])
+	require.Contains(t, entries, upk+".nk")
+	require.NotContains(t, entries, bpk+".nk")
+
+	_, _, err = ExecuteCmd(createExportKeysCmd(), "--accounts", "--output-file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify --force to overwrite")
+
+	_, _, err = ExecuteCmd(createExportKeysCmd(), "--accounts", "--output-file", fp, "--dir", ts.Dir)
+	require.Error(t, err)
+}
+
+func Test_ExportArchiveEncrypted(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+
+	require.NoError(t, os.Setenv("NSC_TEST_EXPORT_PASSPHRASE", "secret"))
+	defer os.Unsetenv("NSC_TEST_EXPORT_PASSPHRASE")
+
+	fp := filepath.Join(ts.Dir, "keys.tgz.enc")
+	_, _, err := ExecuteCmd(createExportKeysCmd(), "--accounts", "--output-file", fp, "--passphrase-env", "NSC_TEST_EXPORT_PASSPHRASE")
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(d, []byte(keysArchiveMagic)))
+	_, err = decryptKeysArchive(d, "wrong")
+	require.Error(t, err)
+	tgz, err := decryptKeysArchive(d, "secret")
+	require.NoError(t, err)
+
+	seed, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+	require.Equal(t, seed, archiveSeeds(t, tgz)[apk+".nk"])
+
+	_, _, err = ExecuteCmd(createExportKeysCmd(), "--accounts", "--output-file", fp+"2", "--passphrase-env", "NSC_TEST_UNSET_PASSPHRASE")
+	require.Error(t, err)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+)
+
+var ExternalSignerFlag string
+
+var errExternalKey = errors.New("the private key is held by the external signer")
+
+// externalSigner is a key pair whose signatures are made by running a
+// command, the command is given the path of a file holding the bytes to
+// sign and prints the base64 encoded ed25519 signature
+type externalSigner struct {
+	command string
+	pub     string
+}
+
+// newExternalSigner returns an external signer for kind. The public key is
+// the one in --private-key, or the operator or account of the context.
+func newExternalSigner(ctx ActionCtx, kind nkeys.PrefixByte) (nkeys.KeyPair, error) {
+	if strings.TrimSpace(ExternalSignerFlag) == "" {
+		return nil, errors.New("--external-signer requires a command")
+	}
+	pub := KeyPathFlag
+	if pub == "" && keyFileKP != nil {
+		pub, _ = keyFileKP.PublicKey()
+	}
+	if pub == "" {
+		sctx := ctx.StoreCtx()
+		switch kind {
+		case nkeys.PrefixByteOperator:
+			pub = sctx.Operator.PublicKey
+		case nkeys.PrefixByteAccount:
+			pub = sctx.Account.PublicKey
+		}
+	}
+	if pub == "" {
+		return nil, fmt.Errorf("specify the public key of the external %s signer with --private-key", kind.String())
+	}
+	kp, err := nkeys.FromPublicKey(pub)
+	if err != nil || !store.KeyPairTypeOk(kind, kp) {
+		return nil, fmt.Errorf("with --external-signer --private-key must be the public %s key of the signer", kind.String())
+	}
+	return &externalSigner{command: ExternalSignerFlag, pub: pub}, nil
+}
+
+func (e *externalSigner) Seed() ([]byte, error) {
+	return nil, errExternalKey
+}
+
+func (e *externalSigner) PublicKey() (string, error) {
+	return e.pub, nil
+}
+
+func (e *externalSigner) PrivateKey() ([]byte, error) {
+	return nil, errExternalKey
+}
+
+func (e *externalSigner) Sign(input []byte) ([]byte, error) {
+	f, err := ioutil.TempFile("", "nsc_sign")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(input); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	args := strings.Fields(e.command)
+	args = append(args, f.Name())
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("external signer %q failed: %v %s", e.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	out := strings.TrimSpace(stdout.String())
+	sig, err := base64.StdEncoding.DecodeString(out)
+	if err != nil {
+		if sig, err = base64.RawURLEncoding.DecodeString(out); err != nil {
+			return nil, fmt.Errorf("external signer %q didn't print a base64 signature", e.command)
+		}
+	}
+	if err := e.Verify(input, sig); err != nil {
+		return nil, fmt.Errorf("external signer %q returned a signature that doesn't verify with %s", e.command, e.pub)
+	}
+	return sig, nil
+}
+
+func (e *externalSigner) Verify(input []byte, sig []byte) error {
+	kp, err := nkeys.FromPublicKey(e.pub)
+	if err != nil {
+		return err
+	}
+	return kp.Verify(input, sig)
+}
+
+func (e *externalSigner) Wipe() {}
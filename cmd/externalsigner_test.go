@@ -0,0 +1,114 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/require"
+)
+
+const testSignerSeedEnv = "NSC_TEST_EXTERNAL_SIGNER_SEED"
+
+// TestExternalSignerHelper isn't a real test, it is the fake signer run by
+// the script in writeFakeSigner
+func TestExternalSignerHelper(t *testing.T) {
+	seed := os.Getenv(testSignerSeedEnv)
+	if seed == "" {
+		return
+	}
+	kp, err := nkeys.FromSeed([]byte(seed))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	d, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	sig, err := kp.Sign(d)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(sig))
+	os.Exit(0)
+}
+
+// writeFakeSigner writes a script that signs its input with the seed
+func writeFakeSigner(t *testing.T, dir string, seed string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake signer is a shell script")
+	}
+	require.NoError(t, os.Setenv(testSignerSeedEnv, seed))
+	fp := filepath.Join(dir, "signer.sh")
+	script := fmt.Sprintf("#!/bin/sh\nexec %q -test.run='^TestExternalSignerHelper$' -- \"$1\"\n", os.Args[0])
+	require.NoError(t, ioutil.WriteFile(fp, []byte(script), 0700))
+	return fp
+}
+
+func Test_ExternalSignerAddUser(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+	seed, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+
+	signer := writeFakeSigner(t, ts.Dir, seed)
+	defer os.Unsetenv(testSignerSeedEnv)
+	// the account key is only available to the signer
+	require.NoError(t, ts.KeyStore.Remove(apk))
+
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "U", "--external-signer", signer)
+	require.NoError(t, err)
+
+	token, err := ts.Store.ReadRawUserClaim("A", "U")
+	require.NoError(t, err)
+	uc, err := jwt.DecodeUserClaims(string(token))
+	require.NoError(t, err)
+	require.Equal(t, apk, uc.Issuer)
+	require.Equal(t, "U", uc.Name)
+}
+
+func Test_ExternalSignerWrongKey(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	other, _, _ := CreateAccountKey(t)
+	signer := writeFakeSigner(t, ts.Dir, string(other))
+	defer os.Unsetenv(testSignerSeedEnv)
+
+	_, stderr, err := ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "U", "--external-signer", signer)
+	require.Error(t, err)
+	require.Contains(t, stderr, "doesn't verify")
+
+	_, opk, _ := CreateOperatorKey(t)
+	_, _, err = ExecuteCmd(HoistRootFlags(CreateAddUserCmd()), "V", "--external-signer", signer, "--private-key", opk)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be the public account key of the signer")
+}
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nats-io/jwt"
 	"github.com/stretchr/testify/require"
@@ -259,3 +260,50 @@ func Test_InteractiveGenerateActivationPush(t *testing.T) {
 	require.Contains(t, m, id)
 	require.Equal(t, []byte(tok), m[id])
 }
+
+func Test_GenerateActivationServiceExpiry(t *testing.T) {
+	ts := NewTestStore(t, "gen activation")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Service, "q", false)
+	_, pub, _ := CreateAccountKey(t)
+
+	outpath := filepath.Join(ts.Dir, "token.jwt")
+	_, _, err := ExecuteCmd(createGenerateActivationCmd(), "--subject", "q", "--target-account", pub,
+		"--expiry", "7d", "--output-file", outpath)
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(outpath)
+	require.NoError(t, err)
+	s, err := jwt.ParseDecoratedJWT(d)
+	require.NoError(t, err)
+	ac, err := jwt.DecodeActivationClaims(s)
+	require.NoError(t, err)
+	require.Equal(t, pub, ac.Subject)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), ac.Issuer)
+	require.Equal(t, "q", string(ac.ImportSubject))
+	require.Equal(t, jwt.Service, ac.ImportType)
+	require.True(t, ac.Expires > time.Now().Unix())
+}
+
+func Test_GenerateActivationWildcardSubset(t *testing.T) {
+	ts := NewTestStore(t, "gen activation")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	_, pub, _ := CreateAccountKey(t)
+
+	stdout, _, err := ExecuteCmd(createGenerateActivationCmd(), "--subject", "a.b.>", "--target-account", pub)
+	require.NoError(t, err)
+	s, err := jwt.ParseDecoratedJWT([]byte(stdout))
+	require.NoError(t, err)
+	ac, err := jwt.DecodeActivationClaims(s)
+	require.NoError(t, err)
+	require.Equal(t, "a.b.>", string(ac.ImportSubject))
+
+	_, _, err = ExecuteCmd(createGenerateActivationCmd(), "--subject", "b.>", "--target-account", pub)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `a private export for "b.>" was not found`)
+}
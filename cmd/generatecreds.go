@@ -18,6 +18,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 
 	"github.com/nats-io/jwt"
 
@@ -33,12 +35,16 @@ func createGenerateCredsCmd() *cobra.Command {
 		Short:        "Generate a credentials file for an user",
 		Args:         MaxArgs(0),
 		SilenceUsage: true,
-		Example:      `nsc generate creds --account a --name u`,
+		Example: `nsc generate creds --account a --name u
+# write the account public key next to the creds for tooling that preloads the account jwt
+nsc generate creds --account a --name u --output-file u.creds --account-pubkey-out u.account
+# only replace u.creds if the jwt in it expires within a week
+nsc generate creds --account a --name u --output-file u.creds --refresh-if-within 7d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := RunAction(cmd, args, &params); err != nil {
 				return err
 			}
-			if !QuietMode() && params.out != "--" {
+			if !QuietMode() && params.out != "--" && !params.skip {
 				cmd.Printf("Success!! - generated %q\n", params.out)
 			}
 			return nil
@@ -46,6 +52,8 @@ func createGenerateCredsCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&params.user, "name", "n", "", "user name")
 	cmd.Flags().StringVarP(&params.out, "output-file", "o", "--", "output file '--' is stdout")
+	cmd.Flags().StringVarP(&params.accountPubKeyOut, "account-pubkey-out", "", "", "also write the account public key to the specified file")
+	cmd.Flags().StringVarP(&params.refreshIfWithin, "refresh-if-within", "", "", "only regenerate an existing output file if its jwt expires within the duration - #m(inutes), #h(ours), #d(ays), #w(eeks), #M(onths), #y(ears)")
 	params.AccountContextParams.BindFlags(cmd)
 
 	return cmd
@@ -57,10 +65,15 @@ func init() {
 
 type GenerateCredsParams struct {
 	AccountContextParams
-	user      string
-	out       string
-	entityKP  nkeys.KeyPair
-	entityJwt []byte
+	user             string
+	out              string
+	accountPubKeyOut string
+	refreshIfWithin  string
+	skip             bool
+	skipReason       string
+	replace          bool
+	entityKP         nkeys.KeyPair
+	entityJwt        []byte
 }
 
 func (p *GenerateCredsParams) SetDefaults(ctx ActionCtx) error {
@@ -112,6 +125,9 @@ func (p *GenerateCredsParams) Validate(ctx ActionCtx) error {
 	if p.user == "" {
 		return fmt.Errorf("user is required")
 	}
+	if IsStdOut(p.out) && IsStdOut(p.accountPubKeyOut) {
+		return errors.New("--output-file and --account-pubkey-out cannot both be stdout")
+	}
 
 	if !ctx.StoreCtx().Store.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.user)) {
 		return fmt.Errorf("user %q not found in %q", p.user, p.AccountContextParams.Name)
@@ -133,25 +149,93 @@ func (p *GenerateCredsParams) Validate(ctx ActionCtx) error {
 	}
 
 	if p.entityKP == nil {
-		return fmt.Errorf("user was not found - please specify it")
+		return fmt.Errorf("the private key of user %q (%s) is not in the keystore - unable to generate its creds", p.user, uc.Subject)
+	}
+
+	if p.refreshIfWithin != "" {
+		if IsStdOut(p.out) {
+			return errors.New("--refresh-if-within requires --output-file")
+		}
+		if err = p.checkRefresh(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// checkRefresh skips generating the creds if the output file holds creds
+// whose jwt doesn't expire within the refresh window
+func (p *GenerateCredsParams) checkRefresh() error {
+	deadline, err := ParseExpiry(p.refreshIfWithin)
+	if err != nil {
+		return fmt.Errorf("--refresh-if-within %q is invalid: %v", p.refreshIfWithin, err)
+	}
+	d, err := ioutil.ReadFile(p.out)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	token, err := jwt.ParseDecoratedJWT(d)
+	if err != nil {
+		return fmt.Errorf("error parsing creds %q: %v", p.out, err)
+	}
+	uc, err := jwt.DecodeUserClaims(token)
+	if err != nil {
+		return fmt.Errorf("error decoding the jwt in creds %q: %v", p.out, err)
+	}
+	switch {
+	case uc.Expires == 0:
+		p.skip = true
+		p.skipReason = "doesn't expire"
+	case uc.Expires > deadline:
+		p.skip = true
+		p.skipReason = fmt.Sprintf("expires %s", UnixToDate(uc.Expires))
+	default:
+		p.replace = true
+	}
+	return nil
+}
+
 func (p *GenerateCredsParams) Run(ctx ActionCtx) (store.Status, error) {
+	if p.skip {
+		return store.OKStatus("credentials in %q were not refreshed - the jwt %s", AbbrevHomePaths(p.out), p.skipReason), nil
+	}
 	d, err := GenerateConfig(ctx.StoreCtx().Store, p.AccountContextParams.Name, p.user, p.entityKP)
 	if err != nil {
 		return nil, err
 	}
+	// Write doesn't overwrite files, remove the creds being refreshed
+	if p.replace {
+		if err := os.Remove(p.out); err != nil {
+			return nil, err
+		}
+	}
 	if err := Write(p.out, d); err != nil {
 		return nil, err
 	}
-	var s store.Status
+	r := store.NewDetailedReport(true)
 	if !IsStdOut(p.out) {
-		s = store.OKStatus("wrote credentials to %q", AbbrevHomePaths(p.out))
+		r.AddOK("wrote credentials to %q", AbbrevHomePaths(p.out))
+	}
+	if p.accountPubKeyOut != "" {
+		ac, err := ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := Write(p.accountPubKeyOut, []byte(ac.Subject)); err != nil {
+			return nil, err
+		}
+		if !IsStdOut(p.accountPubKeyOut) {
+			r.AddOK("wrote account public key to %q", AbbrevHomePaths(p.accountPubKeyOut))
+		}
+	}
+	if len(r.Details) == 0 {
+		return nil, nil
 	}
-	return s, nil
+	return r, nil
 }
 
 func GenerateConfig(s *store.Store, account string, user string, userKey nkeys.KeyPair) ([]byte, error) {
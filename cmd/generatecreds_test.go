@@ -16,7 +16,9 @@
 package cmd
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/nats-io/jwt"
@@ -172,3 +174,95 @@ func TestGenerateConfig_InteractiveHonorsAccount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "au", uc.Name)
 }
+
+func TestGenerateConfig_AccountPubKeyOut(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "u")
+
+	creds := filepath.Join(ts.Dir, "u.creds")
+	sidecar := filepath.Join(ts.Dir, "u.account")
+	_, _, err := ExecuteCmd(createGenerateCredsCmd(), "--output-file", creds, "--account-pubkey-out", sidecar)
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(sidecar)
+	require.NoError(t, err)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), string(d))
+	_, err = os.Stat(creds)
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createGenerateCredsCmd(), "--account-pubkey-out", "--")
+	require.Error(t, err)
+}
+
+func TestGenerateConfig_RefreshIfWithin(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "--name", "u", "--expiry", "30d")
+	require.NoError(t, err)
+
+	creds := filepath.Join(ts.Dir, "u.creds")
+	// no creds yet so they are generated
+	_, _, err = ExecuteCmd(createGenerateCredsCmd(), "--output-file", creds, "--refresh-if-within", "7d")
+	require.NoError(t, err)
+	old, err := ioutil.ReadFile(creds)
+	require.NoError(t, err)
+
+	// change the stored jwt so a refresh can be detected
+	_, _, err = ExecuteCmd(createEditUserCmd(), "--name", "u", "--tag", "refreshed")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createGenerateCredsCmd(), "--output-file", creds, "--refresh-if-within", "7d")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "were not refreshed")
+	require.NotContains(t, stderr, "Success!!")
+	d, err := ioutil.ReadFile(creds)
+	require.NoError(t, err)
+	require.Equal(t, old, d)
+
+	_, stderr, err = ExecuteCmd(createGenerateCredsCmd(), "--output-file", creds, "--refresh-if-within", "60d")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "Success!!")
+	d, err = ioutil.ReadFile(creds)
+	require.NoError(t, err)
+	require.NotEqual(t, old, d)
+	token, err := jwt.ParseDecoratedJWT(d)
+	require.NoError(t, err)
+	uc, err := jwt.DecodeUserClaims(token)
+	require.NoError(t, err)
+	require.Contains(t, uc.Tags, "refreshed")
+
+	_, _, err = ExecuteCmd(createGenerateCredsCmd(), "--refresh-if-within", "7d")
+	require.Error(t, err)
+}
+
+func TestGenerateConfig_RegenerateLostCreds(t *testing.T) {
+	ts := NewTestStore(t, "operator")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	cp := ts.KeyStore.CalcUserCredsPath("A", "U")
+	require.FileExists(t, cp)
+	require.NoError(t, os.Remove(cp))
+
+	_, _, err := ExecuteCmd(createGenerateCredsCmd(), "--account", "A", "--name", "U", "--output-file", cp)
+	require.NoError(t, err)
+	d, err := ioutil.ReadFile(cp)
+	require.NoError(t, err)
+
+	upk := ts.GetUserPublicKey(t, "A", "U")
+	ukp, err := ts.KeyStore.GetKeyPair(upk)
+	require.NoError(t, err)
+	expected, err := GenerateConfig(ts.Store, "A", "U", ukp)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(d))
+
+	require.NoError(t, ts.KeyStore.Remove(upk))
+	_, _, err = ExecuteCmd(createGenerateCredsCmd(), "--account", "A", "--name", "U")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "private key of user \"U\"")
+	require.Contains(t, err.Error(), "is not in the keystore")
+}
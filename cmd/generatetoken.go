@@ -0,0 +1,198 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createGenerateTokenCmd() *cobra.Command {
+	var params GenerateTokenParams
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Generate a signed activation or generic jwt token",
+		Example: `nsc generate token --type generic --subject <subject> --signer <key> --output-file token.jwt
+nsc generate token --type activation --subject <account public key> --import-subject <subject> --signer <account key>`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.kind, "type", "", "generic", "type of claim to generate (activation|generic)")
+	cmd.Flags().StringVarP(&params.subject, "subject", "s", "", "subject of the claim - the target account public key for activations")
+	cmd.Flags().StringVarP(&params.name, "name", "n", "", "name for the claim")
+	cmd.Flags().StringVarP(&params.importSubject, "import-subject", "", "", "subject of the import enabled by an activation")
+	cmd.Flags().BoolVarP(&params.service, "service", "", false, "activation is for a service")
+	cmd.Flags().StringVarP(&params.signer, "signer", "", "", "operator or account seed, path to a seed, or public key in the keystore used to sign the token")
+	cmd.Flags().StringVarP(&params.out, "output-file", "o", "--", "output file '--' is stdout")
+	params.TimeParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	generateCmd.AddCommand(createGenerateTokenCmd())
+}
+
+type GenerateTokenParams struct {
+	TimeParams
+	kind          string
+	subject       string
+	name          string
+	importSubject string
+	service       bool
+	signer        string
+	signerKP      nkeys.KeyPair
+	out           string
+	token         string
+}
+
+func (p *GenerateTokenParams) SetDefaults(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *GenerateTokenParams) PreInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *GenerateTokenParams) Load(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *GenerateTokenParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *GenerateTokenParams) Validate(ctx ActionCtx) error {
+	var err error
+	if p.subject == "" {
+		return errors.New("subject is required")
+	}
+	if err = p.TimeParams.Validate(); err != nil {
+		return err
+	}
+	if p.signer == "" {
+		return errors.New("signer is required")
+	}
+	p.signerKP, err = p.resolveSigner(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch p.kind {
+	case "activation":
+		if !store.IsPublicKey(nkeys.PrefixByteAccount, p.subject) && p.subject != "public" {
+			return fmt.Errorf("activation subject %q is not a valid account public key", p.subject)
+		}
+		if p.importSubject == "" {
+			return errors.New("activations require an --import-subject")
+		}
+		var vr jwt.ValidationResults
+		jwt.Subject(p.importSubject).Validate(&vr)
+		if vr.IsBlocking(false) {
+			return fmt.Errorf("import subject %q is not valid", p.importSubject)
+		}
+		return p.checkSigner(jwt.NewActivationClaims(p.subject).ExpectedPrefixes())
+	case "generic":
+		if p.importSubject != "" || p.service {
+			return errors.New("--import-subject and --service only apply to activations")
+		}
+		return p.checkSigner([]nkeys.PrefixByte{nkeys.PrefixByteOperator, nkeys.PrefixByteAccount})
+	default:
+		return fmt.Errorf("unsupported token type %q - use activation or generic", p.kind)
+	}
+}
+
+// resolveSigner resolves the signer flag, looking up public keys in the keystore
+func (p *GenerateTokenParams) resolveSigner(ctx ActionCtx) (nkeys.KeyPair, error) {
+	kp, err := store.ResolveKey(p.signer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := kp.Seed(); err == nil {
+		return kp, nil
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	kp, err = ctx.StoreCtx().KeyStore.GetKeyPair(pk)
+	if err != nil {
+		return nil, err
+	}
+	if kp == nil {
+		return nil, fmt.Errorf("unable to find the private key for signer %q in the keystore", pk)
+	}
+	return kp, nil
+}
+
+func (p *GenerateTokenParams) checkSigner(kinds []nkeys.PrefixByte) error {
+	for _, k := range kinds {
+		if store.KeyPairTypeOk(k, p.signerKP) {
+			return nil
+		}
+	}
+	pk, _ := p.signerKP.PublicKey()
+	return fmt.Errorf("a %s token cannot be signed by %q", p.kind, pk)
+}
+
+func (p *GenerateTokenParams) Run(ctx ActionCtx) (store.Status, error) {
+	var err error
+	var claim jwt.Claims
+	switch p.kind {
+	case "activation":
+		ac := jwt.NewActivationClaims(p.subject)
+		ac.Activation.ImportSubject = jwt.Subject(p.importSubject)
+		ac.Activation.ImportType = jwt.Stream
+		if p.service {
+			ac.Activation.ImportType = jwt.Service
+		}
+		claim = ac
+	default:
+		claim = jwt.NewGenericClaims(p.subject)
+	}
+	cd := claim.Claims()
+	cd.Name = p.name
+	cd.NotBefore, _ = p.TimeParams.StartDate()
+	cd.Expires, _ = p.TimeParams.ExpiryDate()
+
+	p.token, err = claim.Encode(p.signerKP)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := jwt.DecorateJWT(p.token)
+	if err != nil {
+		return nil, err
+	}
+	if err := Write(p.out, d); err != nil {
+		return nil, err
+	}
+
+	r := store.NewDetailedReport(true)
+	r.AddOK("generated %s token for %q", p.kind, p.subject)
+	if !IsStdOut(p.out) {
+		r.AddOK("wrote token to %q", AbbrevHomePaths(p.out))
+	}
+	return r, nil
+}
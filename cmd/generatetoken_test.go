@@ -0,0 +1,83 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateGenericToken(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	opk := ts.GetOperatorPublicKey(t)
+	fp := filepath.Join(ts.Dir, "token.jwt")
+	_, _, err := ExecuteCmd(createGenerateTokenCmd(), "--type", "generic", "--subject", "foo",
+		"--signer", opk, "--output-file", fp)
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	token, err := jwt.ParseDecoratedJWT(d)
+	require.NoError(t, err)
+	// decoding verifies the signature
+	gc, err := jwt.DecodeGeneric(token)
+	require.NoError(t, err)
+	require.Equal(t, "foo", gc.Subject)
+	require.Equal(t, opk, gc.Issuer)
+}
+
+func Test_GenerateActivationToken(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, tpk, _ := CreateAccountKey(t)
+
+	stdout, _, err := ExecuteCmd(createGenerateTokenCmd(), "--type", "activation", "--subject", tpk,
+		"--import-subject", "q", "--service", "--signer", ts.GetAccountKeyPath(t, "A"))
+	require.NoError(t, err)
+
+	token, err := jwt.ParseDecoratedJWT([]byte(stdout))
+	require.NoError(t, err)
+	ac, err := jwt.DecodeActivationClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, tpk, ac.Subject)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), ac.Issuer)
+	require.Equal(t, jwt.Subject("q"), ac.ImportSubject)
+	require.Equal(t, jwt.Service, ac.ImportType)
+}
+
+func Test_GenerateTokenSignerRole(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	_, _, err := ExecuteCmd(createGenerateTokenCmd(), "--subject", "foo",
+		"--signer", ts.GetUserSeedKey(t, "A", "U"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "a generic token cannot be signed by")
+
+	_, _, err = ExecuteCmd(createGenerateTokenCmd(), "--type", "bogus", "--subject", "foo",
+		"--signer", ts.GetOperatorPublicKey(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported token type")
+}
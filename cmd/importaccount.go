@@ -0,0 +1,133 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createImportAccountCmd() *cobra.Command {
+	var params ImportAccountParams
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Imports an account jwt issued by the operator",
+		Example: `nsc import account --file account.jwt
+# import an account that is not issued by the operator
+nsc import account --file account.jwt --force`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.file, "file", "f", "", "account jwt file, decorated or raw")
+	cmd.Flags().BoolVarP(&params.force, "force", "", false, "import the account even if it is not issued by the operator or its signing keys")
+
+	return cmd
+}
+
+func init() {
+	importCmd.AddCommand(createImportAccountCmd())
+}
+
+type ImportAccountParams struct {
+	file   string
+	force  bool
+	token  string
+	claim  *jwt.AccountClaims
+	signed bool
+}
+
+func (p *ImportAccountParams) SetDefaults(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ImportAccountParams) PreInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ImportAccountParams) Load(ctx ActionCtx) error {
+	if p.file == "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("--file is required")
+	}
+	d, err := Read(p.file)
+	if err != nil {
+		return err
+	}
+	token, err := jwt.ParseDecoratedJWT(d)
+	if err != nil {
+		return err
+	}
+	p.token = strings.TrimSpace(token)
+	gc, err := jwt.DecodeGeneric(p.token)
+	if err != nil {
+		return fmt.Errorf("error decoding %q: %v", p.file, err)
+	}
+	if gc.Type != jwt.AccountClaim {
+		return fmt.Errorf("%q is not an account jwt", p.file)
+	}
+	p.claim, err = jwt.DecodeAccountClaims(p.token)
+	if err != nil {
+		return fmt.Errorf("error decoding %q: %v", p.file, err)
+	}
+	return nil
+}
+
+func (p *ImportAccountParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ImportAccountParams) Validate(ctx ActionCtx) error {
+	var vr jwt.ValidationResults
+	p.claim.Validate(&vr)
+	for _, i := range vr.Issues {
+		if i.Blocking || i.TimeCheck {
+			return fmt.Errorf("account %q is not valid: %v", p.claim.Name, i.Description)
+		}
+	}
+	s := ctx.StoreCtx().Store
+	if s.HasAccount(p.claim.Name) {
+		return fmt.Errorf("the account %q already exists", p.claim.Name)
+	}
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return err
+	}
+	p.signed = oc.DidSign(p.claim)
+	if !p.signed && !p.force {
+		return fmt.Errorf("account %q is not issued by operator %q or its signing keys - specify --force to import it", p.claim.Name, oc.Name)
+	}
+	return nil
+}
+
+func (p *ImportAccountParams) Run(ctx ActionCtx) (store.Status, error) {
+	r := store.NewDetailedReport(false)
+	if !p.signed {
+		r.AddWarning("account %q is issued by %s which is not the operator or one of its signing keys", p.claim.Name, p.claim.Issuer)
+	}
+	StoreAccountAndUpdateStatus(ctx, p.token, r)
+	if r.HasNoErrors() {
+		r.AddOK("imported account %q", p.claim.Name)
+	}
+	return r, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAccountJwt(t *testing.T, dir string, name string, signer nkeys.KeyPair) (string, string) {
+	_, apk, _ := CreateAccountKey(t)
+	ac := jwt.NewAccountClaims(apk)
+	ac.Name = name
+	token, err := ac.Encode(signer)
+	require.NoError(t, err)
+	d, err := jwt.DecorateJWT(token)
+	require.NoError(t, err)
+	fp := filepath.Join(dir, name+".jwt")
+	require.NoError(t, ioutil.WriteFile(fp, d, 0600))
+	return fp, apk
+}
+
+func Test_ImportAccount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	fp, apk := writeAccountJwt(t, ts.Dir, "A", ts.OperatorKey)
+	_, stderr, err := ExecuteCmd(createImportAccountCmd(), "--file", fp)
+	require.NoError(t, err)
+	require.Contains(t, stderr, `imported account "A"`)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, apk, ac.Subject)
+
+	_, _, err = ExecuteCmd(createImportAccountCmd(), "--file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `the account "A" already exists`)
+}
+
+func Test_ImportAccountForeignOperator(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	_, _, okp := CreateOperatorKey(t)
+	fp, apk := writeAccountJwt(t, ts.Dir, "A", okp)
+	_, _, err := ExecuteCmd(createImportAccountCmd(), "--file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify --force to import it")
+	require.False(t, ts.Store.HasAccount("A"))
+
+	_, stderr, err := ExecuteCmd(createImportAccountCmd(), "--file", fp, "--force")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "which is not the operator or one of its signing keys")
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, apk, ac.Subject)
+}
+
+func Test_ImportAccountNotAnAccount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	d, err := ts.Store.ReadRawUserClaim("A", "U")
+	require.NoError(t, err)
+	fp := filepath.Join(ts.Dir, "u.jwt")
+	require.NoError(t, ioutil.WriteFile(fp, d, 0600))
+
+	_, _, err = ExecuteCmd(createImportAccountCmd(), "--file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not an account jwt")
+}
@@ -16,8 +16,13 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -34,6 +39,8 @@ func createImportKeysCmd() *cobra.Command {
 		Long:  `Imports all nkeys found in a directory`,
 		Example: `nsc import keys --dir <path>
 nsc import keys --recursive --dir <path>
+nsc import keys --input keys.tgz
+nsc import keys --input keys.tgz --passphrase-env BACKUP_PASSPHRASE
 `,
 		Args:         MaxArgs(0),
 		SilenceUsage: false,
@@ -46,7 +53,9 @@ nsc import keys --recursive --dir <path>
 	}
 	cmd.Flags().StringVarP(&params.Dir, "dir", "d", "", "directory to export keys to")
 	cmd.Flags().BoolVarP(&params.Recurse, "recurse", "R", false, "recurse directories")
-	cmd.MarkFlagRequired("dir")
+	cmd.Flags().StringVarP(&params.Input, "input", "", "", "key archive written by export keys --output-file (exclusive of --dir)")
+	cmd.Flags().StringVarP(&params.PassphraseEnv, "passphrase-env", "", "", "name of the environment variable holding the passphrase of an encrypted archive (requires --input)")
+	cmd.Flags().BoolVarP(&params.Overwrite, "overwrite", "", false, "replace keys in the keystore that have a different seed (requires --input)")
 
 	return cmd
 }
@@ -56,8 +65,12 @@ func init() {
 }
 
 type ImportKeysParams struct {
-	Dir     string
-	Recurse bool
+	Dir           string
+	Recurse       bool
+	Input         string
+	PassphraseEnv string
+	Overwrite     bool
+	passphrase    string
 }
 
 func (p *ImportKeysParams) SetDefaults(ctx ActionCtx) error {
@@ -78,6 +91,17 @@ func (p *ImportKeysParams) PostInteractive(ctx ActionCtx) error {
 
 func (p *ImportKeysParams) Validate(ctx ActionCtx) error {
 	var err error
+	if (p.Dir == "") == (p.Input == "") {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("specify one of --dir or --input")
+	}
+	if p.Input != "" {
+		return p.validateInput()
+	}
+	if p.PassphraseEnv != "" || p.Overwrite {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("--passphrase-env and --overwrite require --input")
+	}
 	p.Dir, err = Expand(p.Dir)
 	if err != nil {
 		return err
@@ -93,10 +117,30 @@ func (p *ImportKeysParams) Validate(ctx ActionCtx) error {
 	return nil
 }
 
+func (p *ImportKeysParams) validateInput() error {
+	var err error
+	if p.Input, err = Expand(p.Input); err != nil {
+		return err
+	}
+	if _, err := os.Stat(p.Input); err != nil {
+		return err
+	}
+	if p.PassphraseEnv != "" {
+		p.passphrase = os.Getenv(p.PassphraseEnv)
+		if p.passphrase == "" {
+			return fmt.Errorf("environment variable %q is not set", p.PassphraseEnv)
+		}
+	}
+	return nil
+}
+
 func (p *ImportKeysParams) Run(ctx ActionCtx) (store.Status, error) {
 	var a []*ImportNKeyJob
 	ks := ctx.StoreCtx().KeyStore
 	ctx.CurrentCmd().SilenceUsage = true
+	if p.Input != "" {
+		return p.importArchive(ks)
+	}
 	err := filepath.Walk(p.Dir, func(path string, info os.FileInfo, err error) error {
 		if info.IsDir() && path != p.Dir && !p.Recurse {
 			return filepath.SkipDir
@@ -144,3 +188,98 @@ type ImportNKeyJob struct {
 	keypair     nkeys.KeyPair
 	err         error
 }
+
+type keysArchiveEntry struct {
+	name string
+	seed []byte
+}
+
+// readKeysArchive returns the files in a gzipped tar key archive
+func readKeysArchive(data []byte) ([]keysArchiveEntry, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading key archive: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var entries []keysArchiveEntry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading key archive: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		d, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %q from key archive: %v", hdr.Name, err)
+		}
+		entries = append(entries, keysArchiveEntry{name: hdr.Name, seed: bytes.TrimSpace(d)})
+	}
+	return entries, nil
+}
+
+func sameSeed(kp nkeys.KeyPair, seed []byte) bool {
+	s, err := kp.Seed()
+	return err == nil && bytes.Equal(s, seed)
+}
+
+// importArchive stores the seeds in the key archive, keys already in the
+// keystore with a different seed are only replaced with --overwrite
+func (p *ImportKeysParams) importArchive(ks store.KeyStore) (store.Status, error) {
+	data, err := ioutil.ReadFile(p.Input)
+	if err != nil {
+		return nil, err
+	}
+	if p.passphrase != "" {
+		if data, err = decryptKeysArchive(data, p.passphrase); err != nil {
+			return nil, err
+		}
+	} else if bytes.HasPrefix(data, []byte(keysArchiveMagic)) {
+		return nil, errors.New("the archive is encrypted - specify --passphrase-env")
+	}
+	entries, err := readKeysArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no nkeys found in the archive")
+	}
+
+	r := store.NewDetailedReport(true)
+	for _, e := range entries {
+		kp, err := nkeys.FromSeed(e.seed)
+		if err != nil {
+			r.AddError("failed to import %q: %v", e.name, err)
+			continue
+		}
+		pk, err := kp.PublicKey()
+		if err != nil {
+			r.AddError("failed to import %q: %v", e.name, err)
+			continue
+		}
+		if !nkeys.IsValidPublicOperatorKey(pk) && !nkeys.IsValidPublicAccountKey(pk) && !nkeys.IsValidPublicUserKey(pk) {
+			r.AddError("failed to import %q: not an operator, account or user seed", e.name)
+			continue
+		}
+		if old, err := ks.GetKeyPair(pk); err == nil && old != nil && !sameSeed(old, e.seed) {
+			if !p.Overwrite {
+				r.AddError("%s is in the keystore with a different seed - specify --overwrite to replace it", pk)
+				continue
+			}
+			if err := ks.Remove(pk); err != nil {
+				r.AddError("failed to replace %s: %v", pk, err)
+				continue
+			}
+		}
+		if _, err := ks.Store(kp); err != nil {
+			r.AddError("failed to import %q: %v", e.name, err)
+			continue
+		}
+		r.AddOK("%s was added to the keystore", pk)
+	}
+	return r, nil
+}
@@ -17,10 +17,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
 	"github.com/stretchr/testify/require"
 )
 
@@ -109,3 +111,105 @@ func Test_ImportKeyRecursive(t *testing.T) {
 		require.NotNil(t, nk)
 	}
 }
+
+// exportArchive writes the keys of the store to an archive and switches
+// the keystore to an empty directory
+func exportArchive(t *testing.T, ts *TestStore, args ...string) (string, func()) {
+	fp := filepath.Join(ts.Dir, "keys.tgz")
+	args = append([]string{"--all", "--output-file", fp}, args...)
+	_, _, err := ExecuteCmd(createExportKeysCmd(), args...)
+	require.NoError(t, err)
+
+	old := os.Getenv(store.NKeysPathEnv)
+	require.NoError(t, os.Setenv(store.NKeysPathEnv, filepath.Join(ts.Dir, "restored")))
+	return fp, func() {
+		os.Setenv(store.NKeysPathEnv, old)
+	}
+}
+
+func Test_ImportKeysArchive(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	pks := []string{ts.GetAccountPublicKey(t, "A"), ts.GetUserPublicKey(t, "A", "U")}
+
+	fp, restore := exportArchive(t, ts)
+	defer restore()
+	for _, pk := range pks {
+		require.False(t, ts.KeyStore.HasPrivateKey(pk))
+	}
+
+	// the root flags are merged to catch flags clashing with them
+	_, stderr, err := ExecuteCmd(HoistRootFlags(createImportKeysCmd()), "--input", fp)
+	require.NoError(t, err)
+	for _, pk := range pks {
+		require.True(t, ts.KeyStore.HasPrivateKey(pk))
+		require.Contains(t, stderr, fmt.Sprintf("%s was added to the keystore", pk))
+	}
+
+	// importing the same archive again is fine
+	_, _, err = ExecuteCmd(createImportKeysCmd(), "--input", fp)
+	require.NoError(t, err)
+}
+
+func Test_ImportKeysArchiveEncrypted(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+
+	require.NoError(t, os.Setenv("NSC_TEST_IMPORT_PASSPHRASE", "secret"))
+	defer os.Unsetenv("NSC_TEST_IMPORT_PASSPHRASE")
+	fp, restore := exportArchive(t, ts, "--passphrase-env", "NSC_TEST_IMPORT_PASSPHRASE")
+	defer restore()
+
+	_, _, err := ExecuteCmd(createImportKeysCmd(), "--input", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify --passphrase-env")
+
+	_, _, err = ExecuteCmd(createImportKeysCmd(), "--input", fp, "--passphrase-env", "NSC_TEST_IMPORT_PASSPHRASE")
+	require.NoError(t, err)
+	require.True(t, ts.KeyStore.HasPrivateKey(apk))
+}
+
+func Test_ImportKeysArchiveCollision(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	apk := ts.GetAccountPublicKey(t, "A")
+	seed, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+
+	fp, restore := exportArchive(t, ts)
+	defer restore()
+
+	// a seed can only produce one public key, so the collision is a
+	// keystore file holding the seed of another key
+	other, _, _ := CreateAccountKey(t)
+	kf := ts.KeyStore.GetKeyPath(apk)
+	require.NoError(t, MaybeMakeDir(filepath.Dir(kf)))
+	require.NoError(t, Write(kf, other))
+
+	_, stderr, err := ExecuteCmd(createImportKeysCmd(), "--input", fp)
+	require.Error(t, err)
+	require.Contains(t, stderr, "specify --overwrite to replace it")
+
+	_, _, err = ExecuteCmd(createImportKeysCmd(), "--input", fp, "--overwrite")
+	require.NoError(t, err)
+	s, err := ts.KeyStore.GetSeed(apk)
+	require.NoError(t, err)
+	require.Equal(t, seed, s)
+}
+
+func Test_ImportKeysInputOrDir(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(createImportKeysCmd())
+	require.Error(t, err)
+	_, _, err = ExecuteCmd(createImportKeysCmd(), "--dir", ts.Dir, "--input", filepath.Join(ts.Dir, "keys.tgz"))
+	require.Error(t, err)
+	_, _, err = ExecuteCmd(createImportKeysCmd(), "--dir", ts.Dir, "--overwrite")
+	require.Error(t, err)
+}
@@ -60,6 +60,7 @@ func createInitCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&params.Name, "name", "n", "", "name used for the operator, account and user")
 	cmd.Flags().StringVarP(&params.AccountServerURL, "url", "u", "", "operator account server url")
 	cmd.Flags().StringVarP(&params.ManagedOperatorName, "remote-operator", "o", "", "remote well-known operator")
+	cmd.Flags().BoolVarP(&params.IntoExisting, "into-existing", "", false, "add the account and user to the current operator's store instead of creating an operator")
 	HoistRootFlags(cmd)
 	return cmd
 }
@@ -74,6 +75,8 @@ type InitCmdParams struct {
 	Name                string
 	ManagedOperatorName string
 	CreateOperator      bool
+	IntoExisting        bool
+	existing            bool
 	Operator            keys
 	Account             keys
 	User                keys
@@ -94,7 +97,8 @@ func (p *InitCmdParams) init(cmd *cobra.Command) error {
 	if !cmd.Flag("dir").Changed &&
 		!cmd.Flag("name").Changed &&
 		!cmd.Flag("url").Changed &&
-		!cmd.Flag("remote-operator").Changed {
+		!cmd.Flag("remote-operator").Changed &&
+		!cmd.Flag("into-existing").Changed {
 		p.Prompt = true
 	}
 
@@ -238,6 +242,10 @@ func (p *InitCmdParams) createStore(cmd *cobra.Command) error {
 	var onk store.NamedKey
 	onk.Name = p.Name
 
+	if p.CreateOperator && p.IntoExisting {
+		return p.loadExistingStore()
+	}
+
 	if p.CreateOperator {
 		p.Operator.KP, err = nkeys.CreateOperator()
 		if err != nil {
@@ -296,8 +304,40 @@ func (p *InitCmdParams) createStore(cmd *cobra.Command) error {
 	return GetConfig().Save()
 }
 
+// loadExistingStore uses the store of the current operator, the
+// operator's key is required to sign the account
+func (p *InitCmdParams) loadExistingStore() error {
+	name := GetConfig().Operator
+	if name == "" {
+		return fmt.Errorf("no operator store found in %q to add the account into", AbbrevHomePaths(p.Dir))
+	}
+	s, err := GetConfig().LoadStore(name)
+	if err != nil {
+		return fmt.Errorf("error loading the store for operator %q: %v", name, err)
+	}
+	if s.IsManaged() {
+		return fmt.Errorf("operator %q is managed - use --remote-operator or --url to add accounts to it", name)
+	}
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return err
+	}
+	ks := store.NewKeyStore(name)
+	kp, err := ks.GetKeyPair(oc.Subject)
+	if err != nil {
+		return err
+	}
+	if kp == nil {
+		return fmt.Errorf("the key for operator %q is not in the keystore - the store cannot be used to add accounts", name)
+	}
+	p.Operator.KP = kp
+	p.Store = s
+	p.existing = true
+	return nil
+}
+
 func (p *InitCmdParams) setOperatorDefaults(ctx ActionCtx) error {
-	if p.CreateOperator {
+	if p.CreateOperator && !p.existing {
 		oc, err := ctx.StoreCtx().Store.ReadOperatorClaim()
 		if err != nil {
 			return err
@@ -382,7 +422,9 @@ func (p *InitCmdParams) createUser(ctx ActionCtx) error {
 func (p *InitCmdParams) Run(ctx ActionCtx) (store.Status, error) {
 	ctx.CurrentCmd().SilenceUsage = true
 	r := store.NewDetailedReport(true)
-	if p.CreateOperator {
+	if p.existing {
+		r.AddOK("using existing operator %s", GetConfig().Operator)
+	} else if p.CreateOperator {
 		if err := p.setOperatorDefaults(ctx); err != nil {
 			return nil, err
 		}
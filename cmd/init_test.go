@@ -261,3 +261,42 @@ func Test_InitDuplicate(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "an account named \"A\" already exists")
 }
+
+func Test_InitIntoExisting(t *testing.T) {
+	ts := NewTestStore(t, "X")
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(createInitCmd(), "--name", "O")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createInitCmd(), "--name", "A", "--into-existing")
+	require.NoError(t, err)
+
+	ts.VerifyAccount(t, "O", "O", true)
+	ts.VerifyAccount(t, "O", "A", true)
+	ts.VerifyUser(t, "O", "A", "A", true)
+
+	s, err := GetConfig().LoadStore("O")
+	require.NoError(t, err)
+	oc, err := s.ReadOperatorClaim()
+	require.NoError(t, err)
+	ac, err := s.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, oc.Subject, ac.Issuer)
+
+	_, _, err = ExecuteCmd(createInitCmd(), "--name", "A", "--into-existing")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "an account named \"A\" already exists")
+}
+
+func Test_InitIntoExistingManaged(t *testing.T) {
+	as, m := RunTestAccountServer(t)
+	defer as.Close()
+
+	ts := NewTestStoreWithOperatorJWT(t, string(m["operator"]))
+	defer ts.Done(t)
+
+	_, _, err := ExecuteCmd(createInitCmd(), "--name", "A", "--into-existing")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is managed")
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nkeys"
+	"github.com/spf13/cobra"
+)
+
+func createKeysFingerprintCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "fingerprint <pubkey>",
+		Short:        "Print a short fingerprint for a public key",
+		Example:      "nsc keys fingerprint UAJ...",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fp, err := KeyFingerprint(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.Println(fp)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func init() {
+	keysCmd.AddCommand(createKeysFingerprintCmd())
+}
+
+// KeyFingerprint returns a short fingerprint for a public nkey, the first
+// eight bytes of the sha256 of the key as colon separated hex
+func KeyFingerprint(pubkey string) (string, error) {
+	if _, err := nkeys.FromPublicKey(pubkey); err != nil {
+		return "", fmt.Errorf("%q is not a valid public key", pubkey)
+	}
+	sum := sha256.Sum256([]byte(pubkey))
+	var parts []string
+	for _, b := range sum[:8] {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, ":"), nil
+}
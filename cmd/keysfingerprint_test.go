@@ -0,0 +1,46 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_KeysFingerprintStable(t *testing.T) {
+	_, pk, _ := CreateUserKey(t)
+
+	fp, err := KeyFingerprint(pk)
+	require.NoError(t, err)
+	fp2, err := KeyFingerprint(pk)
+	require.NoError(t, err)
+	require.Equal(t, fp, fp2)
+	require.Len(t, strings.Split(fp, ":"), 8)
+
+	_, opk, _ := CreateUserKey(t)
+	ofp, err := KeyFingerprint(opk)
+	require.NoError(t, err)
+	require.NotEqual(t, fp, ofp)
+
+	_, stderr, err := ExecuteCmd(createKeysFingerprintCmd(), pk)
+	require.NoError(t, err)
+	require.Equal(t, fp, strings.TrimSpace(stderr))
+
+	_, _, err = ExecuteCmd(createKeysFingerprintCmd(), "bogus")
+	require.Error(t, err)
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createKeysRotateCmd() *cobra.Command {
+	var params KeysRotateParams
+	cmd := &cobra.Command{
+		Use:          "rotate",
+		Short:        "Replace the key of a user",
+		Example:      "nsc keys rotate --account A --user u",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.user, "user", "u", "", "user name")
+	params.AccountContextParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	keysCmd.AddCommand(createKeysRotateCmd())
+}
+
+// KeysRotateParams re-issues a user with a new key, signed by the key
+// that issued the user
+type KeysRotateParams struct {
+	AccountContextParams
+	user     string
+	claim    *jwt.UserClaims
+	signerKP nkeys.KeyPair
+}
+
+func (p *KeysRotateParams) SetDefaults(ctx ActionCtx) error {
+	p.AccountContextParams.SetDefaults(ctx)
+	return nil
+}
+
+func (p *KeysRotateParams) PreInteractive(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Edit(ctx); err != nil {
+		return err
+	}
+	if p.user == "" {
+		p.user, err = ctx.StoreCtx().PickUser(p.AccountContextParams.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *KeysRotateParams) Load(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	if p.user == "" {
+		n := ctx.StoreCtx().DefaultUser(p.AccountContextParams.Name)
+		if n != nil {
+			p.user = *n
+		}
+	}
+	if p.user == "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("user is required")
+	}
+	if !ctx.StoreCtx().Store.Has(store.Accounts, p.AccountContextParams.Name, store.Users, store.JwtName(p.user)) {
+		return fmt.Errorf("user %q not found in account %q", p.user, p.AccountContextParams.Name)
+	}
+	p.claim, err = ctx.StoreCtx().Store.ReadUserClaim(p.AccountContextParams.Name, p.user)
+	return err
+}
+
+func (p *KeysRotateParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *KeysRotateParams) Validate(ctx ActionCtx) error {
+	var err error
+	p.signerKP, err = ctx.StoreCtx().KeyStore.GetKeyPair(p.claim.Issuer)
+	if err != nil {
+		return err
+	}
+	if p.signerKP == nil {
+		return fmt.Errorf("the key %q that issued user %q is not in the keystore", p.claim.Issuer, p.user)
+	}
+	return nil
+}
+
+func (p *KeysRotateParams) Run(ctx ActionCtx) (store.Status, error) {
+	ukp, err := nkeys.CreateUser()
+	if err != nil {
+		return nil, err
+	}
+	upk, err := ukp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	old := p.claim.Subject
+
+	uc := *p.claim
+	uc.Subject = upk
+	token, err := uc.Encode(p.signerKP)
+	if err != nil {
+		return nil, err
+	}
+
+	r := store.NewDetailedReport(true)
+	ks := ctx.StoreCtx().KeyStore
+	fp, err := ks.Store(ukp)
+	if err != nil {
+		return nil, err
+	}
+	r.AddOK("generated and stored user key %q", AbbrevHomePaths(fp))
+
+	rs, err := ctx.StoreCtx().Store.StoreClaim([]byte(token))
+	if rs != nil {
+		r.Add(rs)
+	}
+	if err != nil {
+		r.AddFromError(err)
+		return r, err
+	}
+	r.AddOK("rotated user %q key %s -> %s", p.user, old, upk)
+
+	if ks.HasPrivateKey(old) {
+		if err := ks.Remove(old); err != nil {
+			r.AddFromError(err)
+			return r, err
+		}
+		r.AddOK("removed old user key %s", old)
+	}
+
+	d, err := GenerateConfig(ctx.StoreCtx().Store, p.AccountContextParams.Name, p.user, ukp)
+	if err != nil {
+		r.AddFromError(err)
+		return r, err
+	}
+	cp, err := ks.MaybeStoreUserCreds(p.AccountContextParams.Name, p.user, d)
+	if err != nil {
+		r.AddFromError(err)
+		return r, err
+	}
+	r.AddOK("generated user creds file %q", AbbrevHomePaths(cp))
+	return r, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_KeysRotateUser(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "u")
+	ts.AddUser(t, "A", "v")
+
+	araw, err := ts.Store.ReadRawAccountClaim("A")
+	require.NoError(t, err)
+	before, err := ts.Store.ReadUserClaim("A", "u")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createKeysRotateCmd(), "--account", "A", "--user", "u")
+	require.NoError(t, err)
+
+	uc, err := ts.Store.ReadUserClaim("A", "u")
+	require.NoError(t, err)
+	require.NotEqual(t, before.Subject, uc.Subject)
+	require.Equal(t, before.Issuer, uc.Issuer)
+	require.Equal(t, "u", uc.Name)
+	require.Contains(t, stderr, before.Subject+" -> "+uc.Subject)
+
+	require.True(t, ts.KeyStore.HasPrivateKey(uc.Subject))
+	require.False(t, ts.KeyStore.HasPrivateKey(before.Subject))
+
+	d, err := ioutil.ReadFile(ts.KeyStore.GetUserCredsPath("A", "u"))
+	require.NoError(t, err)
+	token, err := jwt.ParseDecoratedJWT(d)
+	require.NoError(t, err)
+	cuc, err := jwt.DecodeUserClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, uc.Subject, cuc.Subject)
+
+	// the account is untouched
+	after, err := ts.Store.ReadRawAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, araw, after)
+}
+
+func Test_KeysRotateUserNotFound(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(createKeysRotateCmd(), "--account", "A", "--user", "x")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `user "x" not found`)
+}
@@ -16,9 +16,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"time"
 
 	cli "github.com/nats-io/cliprompts/v2"
 	"github.com/nats-io/jwt"
@@ -38,6 +41,10 @@ func init() {
 	listCmd.AddCommand(createListOperatorsCmd())
 	listCmd.AddCommand(createListAccountsCmd())
 	listCmd.AddCommand(createListUsersCmd())
+	listCmd.AddCommand(createListImportsCmd())
+	listCmd.AddCommand(createListSigningKeysCmd())
+	listCmd.AddCommand(createListEverythingCmd())
+	listCmd.AddCommand(createListOrphanedCredsCmd())
 }
 
 type listEntry struct {
@@ -93,6 +100,9 @@ func createListOperatorsCmd() *cobra.Command {
 
 func createListAccountsCmd() *cobra.Command {
 	var operator string
+	var sortBy string
+	var reverse bool
+	var asJSON bool
 
 	cmd := &cobra.Command{
 		Use:   "accounts",
@@ -133,12 +143,26 @@ func createListAccountsCmd() *cobra.Command {
 				}
 				i.claims = ac
 			}
+			if err := sortAccountEntries(s, infos, sortBy, reverse); err != nil {
+				return err
+			}
+			if asJSON {
+				d, err := listEntitiesJSON(infos)
+				if err != nil {
+					return err
+				}
+				cmd.Println(d)
+				return nil
+			}
 			cmd.Println(listEntities("Accounts", infos, config.Account))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().StringVarP(&sortBy, "sort", "", "name", "sort accounts by name|expiry|users|issued")
+	cmd.Flags().BoolVarP(&reverse, "reverse", "", false, "reverse the sort order")
+	cmd.Flags().BoolVarP(&asJSON, "json", "", false, "print the accounts as JSON")
 
 	return cmd
 }
@@ -146,11 +170,35 @@ func createListAccountsCmd() *cobra.Command {
 func createListUsersCmd() *cobra.Command {
 	var operator string
 	var account string
+	var permSubject string
+	var reviewDue bool
+	var count bool
+	var all bool
+	var tags []string
+	var noExpiry bool
+	var asJSON bool
 	cmd := &cobra.Command{
 		Use:   "users",
 		Short: "List users",
-		Args:  MaxArgs(0),
+		Example: `nsc list users
+# list users that are allowed to publish or subscribe to a subject
+nsc list users --permission-contains orders.new
+# list users that are past the review date set with --tag-expiry
+nsc list users --review-due
+# count the users with a tag in each account
+nsc list users --count --all --tag prod
+nsc list users --json`,
+		Args: MaxArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && !count {
+				return errors.New("--all requires --count")
+			}
+			if all && account != "" {
+				return errors.New("--all and --account are exclusive")
+			}
+			if asJSON && (count || permSubject != "" || reviewDue) {
+				return errors.New("--json cannot be combined with --count, --permission-contains or --review-due")
+			}
 			config := GetConfig()
 			if config.StoreRoot == "" {
 				return fmt.Errorf("no store set - `%s env --store <dir>`", GetToolName())
@@ -168,7 +216,7 @@ func createListUsersCmd() *cobra.Command {
 					return err
 				}
 			}
-			if config.Account == "" {
+			if config.Account == "" && !all {
 				return fmt.Errorf("no account set - `%s env --account <name>`", GetToolName())
 			}
 
@@ -177,39 +225,195 @@ func createListUsersCmd() *cobra.Command {
 				return err
 			}
 
-			names, err := s.ListEntries(store.Accounts, config.Account, store.Users)
-			if err != nil {
-				return err
+			accounts := []string{config.Account}
+			if all {
+				if accounts, err = config.ListAccounts(); err != nil {
+					return err
+				}
+				sort.Strings(accounts)
 			}
-			sort.Strings(names)
-
-			var infos []*listEntry
-			for _, v := range names {
-				var i listEntry
-				i.name = v
-				infos = append(infos, &i)
-				uc, err := s.ReadUserClaim(config.Account, v)
+			var counts []userCount
+			for _, a := range accounts {
+				infos, err := loadUserEntries(s, a)
 				if err != nil {
-					i.err = err
-					continue
+					return err
 				}
-				if uc == nil {
-					i.err = fmt.Errorf("%q jwt not found", v)
+				infos = filterUserEntries(infos, tags, noExpiry)
+				if count {
+					counts = append(counts, userCount{account: a, users: len(infos)})
 					continue
 				}
-				i.claims = uc
+				if permSubject != "" {
+					cmd.Println(listPermissionMatches(permSubject, infos))
+					return nil
+				}
+				if reviewDue {
+					cmd.Println(listReviewDue(infos, time.Now()))
+					return nil
+				}
+				if asJSON {
+					d, err := listEntitiesJSON(infos)
+					if err != nil {
+						return err
+					}
+					cmd.Println(d)
+					return nil
+				}
+				cmd.Println(listEntities("Users", infos, config.Account))
+			}
+			if count {
+				cmd.Println(renderUserCounts(counts, all))
 			}
-			cmd.Println(listEntities("Users", infos, config.Account))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
 	cmd.Flags().StringVarP(&account, "account", "a", "", "account name")
+	cmd.Flags().StringVarP(&permSubject, "permission-contains", "", "", "only list users with a pub or sub allow permission matching the subject")
+	cmd.Flags().BoolVarP(&reviewDue, "review-due", "", false, "only list users whose review date is past")
+	cmd.Flags().BoolVarP(&count, "count", "", false, "print the number of users instead of listing them")
+	cmd.Flags().BoolVarP(&all, "all", "", false, "count the users in each account of the operator (requires --count)")
+	cmd.Flags().StringSliceVarP(&tags, "tag", "", nil, "only include users with the tags - comma separated list or option can be specified multiple times")
+	cmd.Flags().BoolVarP(&noExpiry, "no-expiry", "", false, "only include users that don't expire")
+	cmd.Flags().BoolVarP(&asJSON, "json", "", false, "print the users as JSON")
 
 	return cmd
 }
 
+func loadUserEntries(s *store.Store, account string) ([]*listEntry, error) {
+	names, err := s.ListEntries(store.Accounts, account, store.Users)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var infos []*listEntry
+	for _, v := range names {
+		var i listEntry
+		i.name = v
+		infos = append(infos, &i)
+		uc, err := s.ReadUserClaim(account, v)
+		if err != nil {
+			i.err = err
+			continue
+		}
+		if uc == nil {
+			i.err = fmt.Errorf("%q jwt not found", v)
+			continue
+		}
+		i.claims = uc
+	}
+	return infos, nil
+}
+
+// filterUserEntries returns the users having all the tags, and no
+// expiration if noExpiry is set - users that failed to load are only
+// kept when there's nothing to filter
+func filterUserEntries(infos []*listEntry, tags []string, noExpiry bool) []*listEntry {
+	if len(tags) == 0 && !noExpiry {
+		return infos
+	}
+	var filtered []*listEntry
+	for _, i := range infos {
+		uc, ok := i.claims.(*jwt.UserClaims)
+		if i.err != nil || !ok {
+			continue
+		}
+		if noExpiry && uc.Expires > 0 {
+			continue
+		}
+		match := true
+		for _, t := range tags {
+			if !uc.Tags.Contains(t) {
+				match = false
+				break
+			}
+		}
+		if match {
+			filtered = append(filtered, i)
+		}
+	}
+	return filtered
+}
+
+type userCount struct {
+	account string
+	users   int
+}
+
+func renderUserCounts(counts []userCount, perAccount bool) string {
+	total := 0
+	for _, c := range counts {
+		total += c.users
+	}
+	if !perAccount {
+		return fmt.Sprintf("%d", total)
+	}
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("User Counts")
+	table.AddHeaders("Account", "Users")
+	for _, c := range counts {
+		table.AddRow(c.account, c.users)
+	}
+	table.AddSeparator()
+	table.AddRow("Total", total)
+	return table.Render()
+}
+
+// sortAccountEntries orders the account entries ascending by the specified key
+func sortAccountEntries(s *store.Store, infos []*listEntry, by string, reverse bool) error {
+	var key func(e *listEntry) int64
+	switch by {
+	case "name":
+		// names are already sorted
+	case "expiry":
+		key = func(e *listEntry) int64 {
+			if e.claims == nil {
+				return 0
+			}
+			// accounts that never expire sort after the ones that do
+			if e.claims.Claims().Expires == 0 {
+				return math.MaxInt64
+			}
+			return e.claims.Claims().Expires
+		}
+	case "issued":
+		key = func(e *listEntry) int64 {
+			if e.claims == nil {
+				return 0
+			}
+			return e.claims.Claims().IssuedAt
+		}
+	case "users":
+		counts := make(map[string]int64)
+		for _, e := range infos {
+			users, err := s.ListEntries(store.Accounts, e.name, store.Users)
+			if err != nil {
+				return err
+			}
+			counts[e.name] = int64(len(users))
+		}
+		key = func(e *listEntry) int64 {
+			return counts[e.name]
+		}
+	default:
+		return fmt.Errorf("unsupported sort %q - use name, expiry, users or issued", by)
+	}
+	if key != nil {
+		sort.SliceStable(infos, func(i, j int) bool {
+			return key(infos[i]) < key(infos[j])
+		})
+	}
+	if reverse {
+		for i, j := 0, len(infos)-1; i < j; i, j = i+1, j-1 {
+			infos[i], infos[j] = infos[j], infos[i]
+		}
+	}
+	return nil
+}
+
 func listEntities(title string, infos []*listEntry, current string) string {
 	table := tablewriter.CreateTable()
 	table.UTF8Box()
@@ -217,10 +421,10 @@ func listEntities(title string, infos []*listEntry, current string) string {
 	if len(infos) == 0 {
 		table.AddRow("No entries defined")
 	} else {
-		table.AddHeaders("Name", "Public Key")
+		table.AddHeaders("Name", "Public Key", "Expires")
 		for _, v := range infos {
 			n := v.name
-			var p string
+			var p, exp string
 			if v.err != nil || v.claims == nil {
 				p = fmt.Sprintf("error loading jwt - %v", v.err)
 			} else {
@@ -231,14 +435,116 @@ func listEntities(title string, infos []*listEntry, current string) string {
 						n = fmt.Sprintf("%s (%s)", n, c.Name)
 					}
 					p = c.Subject
+					exp = RenderDate(c.Expires)
 				}
 			}
 			if n == current {
 				n = cli.Bold(n)
 				p = cli.Bold(p)
 			}
-			table.AddRow(n, p)
+			table.AddRow(n, p, exp)
 		}
 	}
 	return table.Render()
 }
+
+type listEntryJSON struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key,omitempty"`
+	Expires   int64  `json:"expires,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// listEntitiesJSON renders the entries as a JSON array for scripting
+func listEntitiesJSON(infos []*listEntry) (string, error) {
+	entries := []listEntryJSON{}
+	for _, v := range infos {
+		e := listEntryJSON{Name: v.name}
+		if v.err != nil || v.claims == nil {
+			e.Error = fmt.Sprintf("error loading jwt - %v", v.err)
+		} else if c := v.claims.Claims(); c != nil {
+			e.PublicKey = c.Subject
+			e.Expires = c.Expires
+		}
+		entries = append(entries, e)
+	}
+	d, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(d), nil
+}
+
+// permissionMatches returns true if any of the permissions matches the subject
+func permissionMatches(perms jwt.StringList, subject string) bool {
+	for _, v := range perms {
+		if jwt.Subject(subject).IsContainedIn(jwt.Subject(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func listPermissionMatches(subject string, infos []*listEntry) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle(fmt.Sprintf("Users with permissions matching %q", subject))
+	var rows [][]interface{}
+	for _, v := range infos {
+		if v.err != nil || v.claims == nil {
+			continue
+		}
+		uc, ok := v.claims.(*jwt.UserClaims)
+		if !ok {
+			continue
+		}
+		pub := permissionMatches(uc.Pub.Allow, subject)
+		sub := permissionMatches(uc.Sub.Allow, subject)
+		if !pub && !sub {
+			continue
+		}
+		rows = append(rows, []interface{}{v.name, uc.Subject, yesNo(pub), yesNo(sub)})
+	}
+	if len(rows) == 0 {
+		table.AddRow("No matching users")
+	} else {
+		table.AddHeaders("Name", "Public Key", "Pub", "Sub")
+		for _, r := range rows {
+			table.AddRow(r...)
+		}
+	}
+	return table.Render()
+}
+
+func listReviewDue(infos []*listEntry, now time.Time) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Users past their review date")
+	var rows [][]interface{}
+	for _, v := range infos {
+		if v.err != nil || v.claims == nil {
+			continue
+		}
+		d, ok := reviewDate(v.claims.Claims().Tags)
+		if !ok || d.After(now) {
+			continue
+		}
+		rows = append(rows, []interface{}{v.name, v.claims.Claims().Subject, d.Format("2006-01-02")})
+	}
+	if len(rows) == 0 {
+		table.AddRow("No users due for review")
+	} else {
+		table.AddHeaders("Name", "Public Key", "Review Date")
+		for _, r := range rows {
+			table.AddRow(r...)
+		}
+	}
+	return table.Render()
+}
+
+func yesNo(tf bool) string {
+	if tf {
+		return "Yes"
+	}
+	return "No"
+}
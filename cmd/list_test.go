@@ -0,0 +1,216 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListUsersPermissionContains(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "a", "--allow-pub", "foo.>")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "b", "--allow-sub", "foo.bar")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "c", "--allow-pubsub", "baz")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createListUsersCmd(), "--permission-contains", "foo.bar")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	apk := ts.GetUserPublicKey(t, "A", "a")
+	bpk := ts.GetUserPublicKey(t, "A", "b")
+	cpk := ts.GetUserPublicKey(t, "A", "c")
+	require.Contains(t, out, "a "+apk+" Yes No")
+	require.Contains(t, out, "b "+bpk+" No Yes")
+	require.NotContains(t, out, cpk)
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--permission-contains", "nothing")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No matching users")
+}
+
+func Test_ListAccountsSortUsers(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "a1")
+	ts.AddUser(t, "A", "a2")
+	ts.AddAccount(t, "B")
+	ts.AddAccount(t, "C")
+	ts.AddUser(t, "C", "c1")
+
+	_, stderr, err := ExecuteCmd(createListAccountsCmd(), "--sort", "users")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	b := strings.Index(out, ts.GetAccountPublicKey(t, "B"))
+	c := strings.Index(out, ts.GetAccountPublicKey(t, "C"))
+	a := strings.Index(out, ts.GetAccountPublicKey(t, "A"))
+	require.True(t, b != -1 && c != -1 && a != -1)
+	require.True(t, b < c && c < a)
+
+	_, stderr, err = ExecuteCmd(createListAccountsCmd(), "--sort", "users", "--reverse")
+	require.NoError(t, err)
+	out = StripTableDecorations(stderr)
+	b = strings.Index(out, ts.GetAccountPublicKey(t, "B"))
+	c = strings.Index(out, ts.GetAccountPublicKey(t, "C"))
+	a = strings.Index(out, ts.GetAccountPublicKey(t, "A"))
+	require.True(t, a < c && c < b)
+
+	_, _, err = ExecuteCmd(createListAccountsCmd(), "--sort", "bogus")
+	require.Error(t, err)
+}
+
+func Test_ListAccountsSortExpiry(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "--name", "A", "--expiry", "2040-01-01")
+	require.NoError(t, err)
+	ts.AddAccount(t, "B")
+	ts.AddAccount(t, "C")
+	_, _, err = ExecuteCmd(createEditAccount(), "--name", "C", "--expiry", "2030-01-01")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createListAccountsCmd(), "--sort", "expiry")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	a := strings.Index(out, ts.GetAccountPublicKey(t, "A"))
+	b := strings.Index(out, ts.GetAccountPublicKey(t, "B"))
+	c := strings.Index(out, ts.GetAccountPublicKey(t, "C"))
+	require.True(t, a != -1 && b != -1 && c != -1)
+	// B never expires so it is listed last
+	require.True(t, c < a && a < b)
+}
+
+func Test_ListUsersReviewDue(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "a", "--tag-expiry", "2001-02-03")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "b", "--tag-expiry", "2999-12-31")
+	require.NoError(t, err)
+	ts.AddUser(t, "A", "c")
+
+	_, stderr, err := ExecuteCmd(createListUsersCmd(), "--review-due")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, "a "+ts.GetUserPublicKey(t, "A", "a")+" 2001-02-03")
+	require.NotContains(t, out, ts.GetUserPublicKey(t, "A", "b"))
+	require.NotContains(t, out, ts.GetUserPublicKey(t, "A", "c"))
+
+	// moving the review date forward removes the user from the list
+	_, _, err = ExecuteCmd(createEditUserCmd(), "a", "--tag-expiry", "2999-01-01")
+	require.NoError(t, err)
+	uc, err := ts.Store.ReadUserClaim("A", "a")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"review:2999-01-01"}, uc.Tags)
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--review-due")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No users due for review")
+
+	_, _, err = ExecuteCmd(createEditUserCmd(), "a", "--tag-expiry", "12/31/2025")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected yyyy-mm-dd")
+}
+
+func Test_ListUsersCount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "a", "--tag", "prod")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "b", "--tag", "prod", "--expiry", "2999-12-31")
+	require.NoError(t, err)
+	ts.AddUser(t, "A", "c")
+	ts.AddAccount(t, "B")
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "d", "--tag", "PROD")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createListUsersCmd(), "--count", "--account", "A")
+	require.NoError(t, err)
+	require.Equal(t, "3", strings.TrimSpace(stderr))
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--count", "--account", "A", "--tag", "prod")
+	require.NoError(t, err)
+	require.Equal(t, "2", strings.TrimSpace(stderr))
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--count", "--account", "A", "--tag", "prod", "--no-expiry")
+	require.NoError(t, err)
+	require.Equal(t, "1", strings.TrimSpace(stderr))
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--count", "--all", "--tag", "prod")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, "A 2")
+	require.Contains(t, out, "B 1")
+	require.Contains(t, out, "Total 3")
+
+	_, _, err = ExecuteCmd(createListUsersCmd(), "--all")
+	require.Error(t, err)
+}
+
+func Test_ListAccountsAndUsersJSON(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddAccount(t, "B")
+	ts.AddUser(t, "B", "b1")
+	ts.AddUser(t, "B", "b2")
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "--name", "b3", "--expiry", "2030-01-01")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createListAccountsCmd(), "--json")
+	require.NoError(t, err)
+	var accounts []listEntryJSON
+	require.NoError(t, json.Unmarshal([]byte(stderr), &accounts))
+	require.Len(t, accounts, 2)
+	require.Equal(t, "A", accounts[0].Name)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), accounts[0].PublicKey)
+	require.Equal(t, "B", accounts[1].Name)
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--account", "B", "--json")
+	require.NoError(t, err)
+	var users []listEntryJSON
+	require.NoError(t, json.Unmarshal([]byte(stderr), &users))
+	require.Len(t, users, 3)
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+		require.Equal(t, ts.GetUserPublicKey(t, "B", u.Name), u.PublicKey)
+	}
+	require.Equal(t, []string{"b1", "b2", "b3"}, names)
+	require.Zero(t, users[0].Expires)
+	require.NotZero(t, users[2].Expires)
+
+	_, stderr, err = ExecuteCmd(createListUsersCmd(), "--account", "B")
+	require.NoError(t, err)
+	require.Contains(t, StripTableDecorations(stderr), "Name Public Key Expires")
+	require.Contains(t, stderr, RenderDate(users[2].Expires))
+
+	_, _, err = ExecuteCmd(createListUsersCmd(), "--json", "--count")
+	require.Error(t, err)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+// storeDumpVersion is incremented when the layout of the dump changes
+const storeDumpVersion = 1
+
+func createListEverythingCmd() *cobra.Command {
+	var operator string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "everything",
+		Short: "List the operator, accounts and users of the store",
+		Example: `nsc list everything
+nsc list everything --json`,
+		Args: MaxArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return fmt.Errorf("no store set - `%s env --store <dir>`", GetToolName())
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return fmt.Errorf("no operator set - `%s env --operator <name>`", GetToolName())
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			dump, err := loadStoreDump(s)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				d, err := json.MarshalIndent(dump, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(d))
+				return nil
+			}
+			cmd.Println(renderStoreDump(dump))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().BoolVarP(&asJSON, "json", "", false, "print the store as a single JSON document")
+
+	return cmd
+}
+
+type storeDump struct {
+	Version  int           `json:"version"`
+	Operator operatorDump  `json:"operator"`
+	Accounts []accountDump `json:"accounts"`
+}
+
+type operatorDump struct {
+	Name   string              `json:"name"`
+	Claims *jwt.OperatorClaims `json:"claims"`
+}
+
+type accountDump struct {
+	Name   string             `json:"name"`
+	Claims *jwt.AccountClaims `json:"claims"`
+	Users  []userDump         `json:"users"`
+}
+
+type userDump struct {
+	Name   string          `json:"name"`
+	Claims *jwt.UserClaims `json:"claims"`
+}
+
+// loadStoreDump reads the operator and all its accounts and users, the
+// exports, imports and limits are part of the account claims
+func loadStoreDump(s *store.Store) (*storeDump, error) {
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return nil, err
+	}
+	dump := &storeDump{
+		Version:  storeDumpVersion,
+		Operator: operatorDump{Name: s.Info.Name, Claims: oc},
+		Accounts: []accountDump{},
+	}
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(accounts)
+	for _, an := range accounts {
+		ac, err := s.ReadAccountClaim(an)
+		if err != nil {
+			return nil, err
+		}
+		ad := accountDump{Name: an, Claims: ac, Users: []userDump{}}
+		users, err := s.ListEntries(store.Accounts, an, store.Users)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(users)
+		for _, un := range users {
+			uc, err := s.ReadUserClaim(an, un)
+			if err != nil {
+				return nil, err
+			}
+			ad.Users = append(ad.Users, userDump{Name: un, Claims: uc})
+		}
+		dump.Accounts = append(dump.Accounts, ad)
+	}
+	return dump, nil
+}
+
+func renderStoreDump(dump *storeDump) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle(fmt.Sprintf("Operator %q", dump.Operator.Name))
+	if len(dump.Accounts) == 0 {
+		table.AddRow("No accounts defined")
+		return table.Render()
+	}
+	table.AddHeaders("Account", "Public Key", "Users", "Exports", "Imports")
+	for _, a := range dump.Accounts {
+		table.AddRow(a.Name, a.Claims.Subject, len(a.Users), len(a.Claims.Exports), len(a.Claims.Imports))
+	}
+	return table.Render()
+}
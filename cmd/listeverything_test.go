@@ -0,0 +1,72 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListEverythingJSON(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "a1")
+	ts.AddUser(t, "A", "a2")
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	ts.AddAccount(t, "B")
+	ts.AddUser(t, "B", "b1")
+
+	_, stderr, err := ExecuteCmd(createListEverythingCmd(), "--json")
+	require.NoError(t, err)
+
+	var dump storeDump
+	require.NoError(t, json.Unmarshal([]byte(stderr), &dump))
+	require.Equal(t, storeDumpVersion, dump.Version)
+	require.Equal(t, "O", dump.Operator.Name)
+	require.Equal(t, ts.GetOperatorPublicKey(t), dump.Operator.Claims.Subject)
+
+	require.Len(t, dump.Accounts, 2)
+	a := dump.Accounts[0]
+	require.Equal(t, "A", a.Name)
+	require.Equal(t, ts.GetAccountPublicKey(t, "A"), a.Claims.Subject)
+	require.Len(t, a.Claims.Exports, 1)
+	require.Len(t, a.Users, 2)
+	require.Equal(t, "a1", a.Users[0].Name)
+	require.Equal(t, ts.GetUserPublicKey(t, "A", "a1"), a.Users[0].Claims.Subject)
+	require.Equal(t, "a2", a.Users[1].Name)
+
+	b := dump.Accounts[1]
+	require.Equal(t, "B", b.Name)
+	require.Len(t, b.Users, 1)
+	require.Equal(t, "b1", b.Users[0].Name)
+}
+
+func Test_ListEverything(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "a1")
+
+	_, stderr, err := ExecuteCmd(createListEverythingCmd())
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, "Account Public Key Users Exports Imports")
+	require.Contains(t, out, "A "+ts.GetAccountPublicKey(t, "A")+" 1 0 0")
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createListImportsCmd() *cobra.Command {
+	var operator string
+	var account string
+	var broken bool
+	cmd := &cobra.Command{
+		Use:   "imports",
+		Short: "List imports",
+		Example: `nsc list imports
+# list imports that are expired, revoked or from an untrusted account
+nsc list imports --broken --account A`,
+		Args: MaxArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return fmt.Errorf("no store set - `%s env --store <dir>`", GetToolName())
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return fmt.Errorf("no operator set - `%s env --operator <name>`", GetToolName())
+			}
+			if account != "" {
+				if err := config.SetAccount(account); err != nil {
+					return err
+				}
+			}
+			if config.Account == "" {
+				return fmt.Errorf("no account set - `%s env --account <name>`", GetToolName())
+			}
+
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			ac, err := s.ReadAccountClaim(config.Account)
+			if err != nil {
+				return err
+			}
+			statuses, err := checkImports(s, ac)
+			if err != nil {
+				return err
+			}
+			cmd.Println(renderImportStatuses(config.Account, statuses, broken))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().StringVarP(&account, "account", "a", "", "account name")
+	cmd.Flags().BoolVarP(&broken, "broken", "", false, "only list imports that are broken")
+
+	return cmd
+}
+
+type importStatus struct {
+	im     *jwt.Import
+	reason string
+}
+
+// checkImports returns the status of each import in the account, the reason
+// is empty if the import is not broken
+func checkImports(s *store.Store, ac *jwt.AccountClaims) ([]importStatus, error) {
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return nil, err
+	}
+	trusted := map[string]bool{oc.Subject: true}
+	for _, sk := range oc.SigningKeys {
+		trusted[sk] = true
+	}
+
+	names, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make(map[string]*jwt.AccountClaims)
+	for _, n := range names {
+		c, err := s.ReadAccountClaim(n)
+		if err != nil {
+			return nil, err
+		}
+		accounts[c.Subject] = c
+	}
+
+	var statuses []importStatus
+	for _, im := range ac.Imports {
+		statuses = append(statuses, importStatus{im: im, reason: importBrokenReason(ac, im, accounts, trusted)})
+	}
+	return statuses, nil
+}
+
+func importBrokenReason(ac *jwt.AccountClaims, im *jwt.Import, accounts map[string]*jwt.AccountClaims, trusted map[string]bool) string {
+	src := accounts[im.Account]
+	if src == nil {
+		return fmt.Sprintf("source account %s is not known to the operator", im.Account)
+	}
+	if !trusted[src.Issuer] {
+		return fmt.Sprintf("source account %s is not signed by the operator", im.Account)
+	}
+	if im.Token == "" {
+		return ""
+	}
+
+	id := ImportDescriber{*im}
+	act, err := id.LoadActivation()
+	if err != nil {
+		return fmt.Sprintf("activation token is invalid - %v", err)
+	}
+	if act.Expires > 0 && act.Expires < time.Now().Unix() {
+		return fmt.Sprintf("activation token expired %s", RenderDate(act.Expires))
+	}
+	if act.Subject != ac.Subject {
+		return fmt.Sprintf("activation token was issued to %s", act.Subject)
+	}
+	remote := importRemoteSubject(im)
+	for _, e := range src.Exports {
+		if e.Type != im.Type || !remote.IsContainedIn(e.Subject) {
+			continue
+		}
+		if e.IsRevokedAt(ac.Subject, time.Unix(act.IssuedAt, 0)) {
+			return fmt.Sprintf("activation token was revoked by export %q", e.Subject)
+		}
+	}
+	return ""
+}
+
+func renderImportStatuses(account string, statuses []importStatus, broken bool) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	if broken {
+		table.AddTitle(fmt.Sprintf("Broken Imports for account %q", account))
+	} else {
+		table.AddTitle(fmt.Sprintf("Imports for account %q", account))
+	}
+	var rows [][]interface{}
+	for _, v := range statuses {
+		if broken && v.reason == "" {
+			continue
+		}
+		status := "OK"
+		if v.reason != "" {
+			status = v.reason
+		}
+		rows = append(rows, []interface{}{v.im.Name, strings.Title(v.im.Type.String()), string(importRemoteSubject(v.im)), v.im.Account, status})
+	}
+	if len(rows) == 0 {
+		if broken {
+			table.AddRow("No broken imports")
+		} else {
+			table.AddRow("No imports defined")
+		}
+	} else {
+		table.AddHeaders("Name", "Type", "Remote", "Source Account", "Status")
+		for _, r := range rows {
+			table.AddRow(r...)
+		}
+	}
+	return table.Render()
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListImportsBroken(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddExport(t, "A", jwt.Stream, "a.>", false)
+	ts.AddExport(t, "A", jwt.Stream, "b.>", false)
+	ts.AddAccount(t, "B")
+	ts.AddImport(t, "A", "a.>", "B")
+	ts.AddImport(t, "A", "b.>", "B")
+
+	_, out, err := ExecuteCmd(createListImportsCmd(), "--broken", "--account", "B")
+	require.NoError(t, err)
+	require.Contains(t, out, "No broken imports")
+
+	// replace the token of the b.> import with an expired one
+	act := jwt.NewActivationClaims(ts.GetAccountPublicKey(t, "B"))
+	act.ImportSubject = "b.>"
+	act.ImportType = jwt.Stream
+	act.Expires = time.Now().Add(-time.Hour).Unix()
+	token, err := act.Encode(ts.GetAccountKey(t, "A"))
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	for _, im := range ac.Imports {
+		if im.Subject == "b.>" {
+			im.Token = token
+		}
+	}
+	token, err = ac.Encode(ts.OperatorKey)
+	require.NoError(t, err)
+	_, err = ts.Store.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	_, out, err = ExecuteCmd(createListImportsCmd(), "--broken", "--account", "B")
+	require.NoError(t, err)
+	out = StripTableDecorations(out)
+	require.Contains(t, out, "b.>")
+	require.Contains(t, out, "activation token expired")
+	require.NotContains(t, out, "a.>")
+
+	_, out, err = ExecuteCmd(createListImportsCmd(), "--account", "B")
+	require.NoError(t, err)
+	require.Contains(t, out, "a.>")
+	require.Contains(t, out, "OK")
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createListOrphanedCredsCmd() *cobra.Command {
+	var operator string
+	var prune bool
+	cmd := &cobra.Command{
+		Use:   "orphaned-creds",
+		Short: "List creds files in the keystore for users that are not in the store",
+		Example: `nsc list orphaned-creds
+nsc list orphaned-creds --prune`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return fmt.Errorf("no store set - `%s env --store <dir>`", GetToolName())
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return fmt.Errorf("no operator set - `%s env --operator <name>`", GetToolName())
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			orphans, err := findOrphanedCreds(s)
+			if err != nil {
+				return err
+			}
+			cmd.Println(renderOrphanedCreds(orphans))
+			if !prune {
+				return nil
+			}
+			for _, o := range orphans {
+				if err := os.Remove(o.path); err != nil {
+					return err
+				}
+				cmd.Printf("removed %q\n", AbbrevHomePaths(o.path))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().BoolVarP(&prune, "prune", "", false, "delete the orphaned creds files")
+	return cmd
+}
+
+type orphanedCreds struct {
+	account string
+	user    string
+	path    string
+}
+
+// findOrphanedCreds returns the creds files of the operator in the
+// keystore whose user jwt is not in the store
+func findOrphanedCreds(s *store.Store) ([]orphanedCreds, error) {
+	dir := filepath.Join(store.GetKeysDir(), store.CredsDir, s.GetName())
+	accounts, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var orphans []orphanedCreds
+	for _, a := range accounts {
+		if !a.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(dir, a.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), store.CredsExtension) {
+				continue
+			}
+			user := strings.TrimSuffix(f.Name(), store.CredsExtension)
+			if s.Has(store.Accounts, a.Name(), store.Users, store.JwtName(user)) {
+				continue
+			}
+			orphans = append(orphans, orphanedCreds{account: a.Name(), user: user, path: filepath.Join(dir, a.Name(), f.Name())})
+		}
+	}
+	return orphans, nil
+}
+
+func renderOrphanedCreds(orphans []orphanedCreds) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle("Orphaned Creds")
+	if len(orphans) == 0 {
+		table.AddRow("No orphaned creds")
+		return table.Render()
+	}
+	table.AddHeaders("Account", "User", "Path")
+	for _, o := range orphans {
+		table.AddRow(o.account, o.user, AbbrevHomePaths(o.path))
+	}
+	return table.Render()
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListOrphanedCreds(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	ts.AddUser(t, "A", "V")
+
+	stdout, stderr, err := ExecuteCmd(createListOrphanedCredsCmd())
+	require.NoError(t, err)
+	require.Empty(t, stdout)
+	require.Contains(t, stderr, "No orphaned creds")
+
+	require.NoError(t, ts.Store.Delete(store.Accounts, "A", store.Users, store.JwtName("U")))
+	up := ts.KeyStore.CalcUserCredsPath("A", "U")
+	vp := ts.KeyStore.CalcUserCredsPath("A", "V")
+
+	_, stderr, err = ExecuteCmd(createListOrphanedCredsCmd())
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	require.Regexp(t, `A +U `, out)
+	require.NotRegexp(t, `A +V `, out)
+	require.FileExists(t, up)
+
+	_, stderr, err = ExecuteCmd(createListOrphanedCredsCmd(), "--prune")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "removed")
+	_, err = os.Stat(up)
+	require.True(t, os.IsNotExist(err))
+	require.FileExists(t, vp)
+
+	_, stderr, err = ExecuteCmd(createListOrphanedCredsCmd())
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No orphaned creds")
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+	"github.com/xlab/tablewriter"
+)
+
+func createListSigningKeysCmd() *cobra.Command {
+	var operator string
+	var account string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "signing-keys",
+		Short: "List the signing keys of an account and the number of users each issued",
+		Example: `nsc list signing-keys --account A
+nsc list signing-keys --account A --json`,
+		Args: MaxArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return fmt.Errorf("no store set - `%s env --store <dir>`", GetToolName())
+			}
+			if operator != "" {
+				if err := config.SetOperator(operator); err != nil {
+					return err
+				}
+			}
+			if config.Operator == "" {
+				return fmt.Errorf("no operator set - `%s env --operator <name>`", GetToolName())
+			}
+			if account != "" {
+				if err := config.SetAccount(account); err != nil {
+					return err
+				}
+			}
+			if config.Account == "" {
+				return fmt.Errorf("no account set - `%s env --account <name>`", GetToolName())
+			}
+
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			keys, err := accountSigningKeys(s, config.Account)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				d, err := json.MarshalIndent(keys, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(d))
+				return nil
+			}
+			cmd.Println(renderSigningKeys(config.Account, keys))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&operator, "operator", "o", "", "operator name")
+	cmd.Flags().StringVarP(&account, "account", "a", "", "account name")
+	cmd.Flags().BoolVarP(&asJSON, "json", "", false, "print the signing keys as JSON")
+
+	return cmd
+}
+
+type signingKeyInfo struct {
+	Key   string `json:"key"`
+	Users int    `json:"users"`
+}
+
+// accountSigningKeys returns the signing keys of the account in the order
+// they are listed in the account, with the number of users each issued
+func accountSigningKeys(s *store.Store, account string) ([]signingKeyInfo, error) {
+	ac, err := s.ReadAccountClaim(account)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	users, err := s.ListEntries(store.Accounts, account, store.Users)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range users {
+		uc, err := s.ReadUserClaim(account, n)
+		if err != nil {
+			return nil, err
+		}
+		counts[uc.Issuer]++
+	}
+	keys := []signingKeyInfo{}
+	for _, k := range ac.SigningKeys {
+		keys = append(keys, signingKeyInfo{Key: k, Users: counts[k]})
+	}
+	return keys, nil
+}
+
+func renderSigningKeys(account string, keys []signingKeyInfo) string {
+	table := tablewriter.CreateTable()
+	table.UTF8Box()
+	table.AddTitle(fmt.Sprintf("Signing Keys for account %q", account))
+	if len(keys) == 0 {
+		table.AddRow("No signing keys defined")
+		return table.Render()
+	}
+	table.AddHeaders("Signing Key", "Users Issued")
+	for _, k := range keys {
+		table.AddRow(k.Key, k.Users)
+	}
+	return table.Render()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListSigningKeys(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, stderr, err := ExecuteCmd(createListSigningKeysCmd(), "--account", "A")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No signing keys defined")
+
+	seed1, pk1, _ := CreateAccountKey(t)
+	_, pk2, _ := CreateAccountKey(t)
+	_, _, err = ExecuteCmd(createEditAccount(), "--sk", pk1, "--sk", pk2)
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U1", "--signing-key", string(seed1))
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U2", "--signing-key", string(seed1))
+	require.NoError(t, err)
+	ts.AddUser(t, "A", "U3")
+
+	_, stderr, err = ExecuteCmd(createListSigningKeysCmd(), "--account", "A")
+	require.NoError(t, err)
+	out := StripTableDecorations(stderr)
+	require.Contains(t, out, pk1+" 2")
+	require.Contains(t, out, pk2+" 0")
+
+	_, stderr, err = ExecuteCmd(createListSigningKeysCmd(), "--account", "A", "--json")
+	require.NoError(t, err)
+	var keys []signingKeyInfo
+	require.NoError(t, json.Unmarshal([]byte(stderr), &keys))
+	require.ElementsMatch(t, []signingKeyInfo{{Key: pk1, Users: 2}, {Key: pk2, Users: 0}}, keys)
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createReissueAccountCmd() *cobra.Command {
+	var params ReissueAccountParams
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Replace the identity key of an account with a generated one",
+		Long: `Replace the identity key of an account with a generated one
+
+The account is re-signed by the operator under the new key, and users
+issued by the old identity key are re-signed with the new one. Creds
+that embed the old user JWTs, imports of the account by other accounts
+and activations issued by the old key stop working and must be updated.`,
+		Example:      `nsc reissue account --account A`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	params.AccountContextParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	reissueCmd.AddCommand(createReissueAccountCmd())
+}
+
+// ReissueAccountParams replaces the identity key of an account and
+// re-signs the users issued by it
+type ReissueAccountParams struct {
+	AccountContextParams
+	SignerParams
+	claim *jwt.AccountClaims
+	users map[string]*jwt.UserClaims
+}
+
+func (p *ReissueAccountParams) SetDefaults(ctx ActionCtx) error {
+	p.AccountContextParams.SetDefaults(ctx)
+	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
+	return nil
+}
+
+func (p *ReissueAccountParams) PreInteractive(ctx ActionCtx) error {
+	if err := p.AccountContextParams.Edit(ctx); err != nil {
+		return err
+	}
+	return p.SignerParams.Edit(ctx)
+}
+
+func (p *ReissueAccountParams) Load(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	s := ctx.StoreCtx().Store
+	p.claim, err = s.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	users, err := s.ListEntries(store.Accounts, p.AccountContextParams.Name, store.Users)
+	if err != nil {
+		return err
+	}
+	p.users = make(map[string]*jwt.UserClaims)
+	for _, n := range users {
+		uc, err := s.ReadUserClaim(p.AccountContextParams.Name, n)
+		if err != nil {
+			return err
+		}
+		p.users[n] = uc
+	}
+	return nil
+}
+
+func (p *ReissueAccountParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ReissueAccountParams) Validate(ctx ActionCtx) error {
+	return p.SignerParams.Resolve(ctx)
+}
+
+func (p *ReissueAccountParams) Run(ctx ActionCtx) (store.Status, error) {
+	kp, err := nkeys.CreateAccount()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	r := store.NewDetailedReport(true)
+	ks := ctx.StoreCtx().KeyStore
+	if _, err := ks.Store(kp); err != nil {
+		return nil, err
+	}
+	old := p.claim.Subject
+	r.AddOK("generated and stored account key %q", pk)
+
+	p.claim.Subject = pk
+	token, err := p.claim.Encode(p.signerKP)
+	if err != nil {
+		return nil, err
+	}
+	StoreAccountAndUpdateStatus(ctx, token, r)
+	if r.HasErrors() {
+		return r, nil
+	}
+	r.AddOK("reissued account %q - its identity changed from %q to %q", p.AccountContextParams.Name, old, pk)
+
+	var names []string
+	for n := range p.users {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		uc := p.users[n]
+		switch {
+		case uc.Issuer == old:
+			resignUser(ctx, r, p.AccountContextParams.Name, n, uc, kp, "")
+		case uc.IssuerAccount == old:
+			// the issuer account must be updated by the signing key
+			if !ks.HasPrivateKey(uc.Issuer) {
+				r.AddWarning("user %q was issued by signing key %q which is not in the keystore - re-sign it to set the new issuer account", n, uc.Issuer)
+				continue
+			}
+			skp, err := ks.GetKeyPair(uc.Issuer)
+			if err != nil {
+				r.AddError("error reading signing key %q: %v", uc.Issuer, err)
+				continue
+			}
+			resignUser(ctx, r, p.AccountContextParams.Name, n, uc, skp, p.claim.Subject)
+		}
+	}
+
+	r.AddWarning("creds and tokens with the old account key %q no longer work - distribute the updated creds files", old)
+	importers, err := p.importers(ctx, old)
+	if err != nil {
+		r.AddFromError(err)
+		return r, nil
+	}
+	for _, n := range importers {
+		r.AddWarning("account %q imports from the old account key - its imports must be updated", n)
+	}
+	if len(p.claim.Exports) > 0 {
+		r.AddWarning("activations issued for the exports of %q are signed by the old key and must be reissued", p.AccountContextParams.Name)
+	}
+	return r, nil
+}
+
+// importers returns the names of the accounts that import from the key
+func (p *ReissueAccountParams) importers(ctx ActionCtx, pk string) ([]string, error) {
+	s := ctx.StoreCtx().Store
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(accounts)
+	var names []string
+	for _, n := range accounts {
+		if n == p.AccountContextParams.Name {
+			continue
+		}
+		ac, err := s.ReadAccountClaim(n)
+		if err != nil {
+			return nil, err
+		}
+		for _, im := range ac.Imports {
+			if im.Account == pk {
+				names = append(names, n)
+				break
+			}
+		}
+	}
+	return names, nil
+}
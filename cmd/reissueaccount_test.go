@@ -0,0 +1,81 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReissueAccount(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	old := ts.GetAccountPublicKey(t, "A")
+	ts.AddUser(t, "A", "U")
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", "generate")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	sk := ac.SigningKeys[0]
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "V", "--signing-key", sk)
+	require.NoError(t, err)
+
+	ts.AddAccount(t, "B")
+	_, _, err = ExecuteCmd(createAddImportCmd(), "--account", "B", "--src-account", old, "--remote-subject", "s.>")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createReissueAccountCmd(), "--account", "A")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `re-signed user "U"`)
+	require.Contains(t, stderr, `re-signed user "V"`)
+	require.Contains(t, stderr, `account "B" imports from the old account key`)
+
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.NotEqual(t, old, ac.Subject)
+	require.True(t, ts.KeyStore.HasPrivateKey(ac.Subject))
+	oc, err := ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	require.True(t, oc.DidSign(ac))
+
+	for _, n := range []string{"U", "V"} {
+		token, err := ts.Store.ReadRawUserClaim("A", n)
+		require.NoError(t, err)
+		uc, err := jwt.DecodeUserClaims(string(token))
+		require.NoError(t, err)
+		require.True(t, ac.DidSign(uc))
+		var vr jwt.ValidationResults
+		uc.Validate(&vr)
+		require.True(t, vr.IsEmpty())
+
+		d, err := ioutil.ReadFile(ts.KeyStore.CalcUserCredsPath("A", n))
+		require.NoError(t, err)
+		ct, err := jwt.ParseDecoratedJWT(d)
+		require.NoError(t, err)
+		require.Equal(t, string(token), ct)
+	}
+	uc, err := ts.Store.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, ac.Subject, uc.Issuer)
+	uc, err = ts.Store.ReadUserClaim("A", "V")
+	require.NoError(t, err)
+	require.Equal(t, sk, uc.Issuer)
+	require.Equal(t, ac.Subject, uc.IssuerAccount)
+}
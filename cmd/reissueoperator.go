@@ -0,0 +1,167 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createReissueOperatorCmd() *cobra.Command {
+	var params ReissueOperatorParams
+	cmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Replace the identity key of the operator with a generated one",
+		Long: `Replace the identity key of the operator with a generated one
+
+The operator and all its accounts are re-signed with the new key. Servers
+trusting the old operator key must be configured with the new operator JWT.
+The old operator private key must be in the keystore.`,
+		Example: `nsc reissue operator --dry-run
+nsc reissue operator`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().BoolVarP(&params.dryRun, "dry-run", "", false, "report the operator and accounts that would be re-signed without changing the store or keystore")
+
+	return cmd
+}
+
+func init() {
+	reissueCmd.AddCommand(createReissueOperatorCmd())
+}
+
+// ReissueOperatorParams replaces the identity key of the operator and
+// re-signs its accounts
+type ReissueOperatorParams struct {
+	dryRun   bool
+	claim    *jwt.OperatorClaims
+	accounts map[string]*jwt.AccountClaims
+}
+
+func (p *ReissueOperatorParams) SetDefaults(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ReissueOperatorParams) PreInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ReissueOperatorParams) Load(ctx ActionCtx) error {
+	var err error
+	s := ctx.StoreCtx().Store
+	p.claim, err = s.ReadOperatorClaim()
+	if err != nil {
+		return err
+	}
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return err
+	}
+	p.accounts = make(map[string]*jwt.AccountClaims)
+	for _, n := range accounts {
+		ac, err := s.ReadAccountClaim(n)
+		if err != nil {
+			return err
+		}
+		p.accounts[n] = ac
+	}
+	return nil
+}
+
+func (p *ReissueOperatorParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *ReissueOperatorParams) Validate(ctx ActionCtx) error {
+	if ctx.StoreCtx().Store.IsManaged() {
+		return fmt.Errorf("operator %q is managed - its identity can't be reissued", p.claim.Name)
+	}
+	// only the owner of the operator can replace its identity
+	if !ctx.StoreCtx().KeyStore.HasPrivateKey(p.claim.Subject) {
+		return fmt.Errorf("the private key of operator %q (%s) is not in the keystore - it is required to reissue the operator", p.claim.Name, p.claim.Subject)
+	}
+	return nil
+}
+
+func (p *ReissueOperatorParams) Run(ctx ActionCtx) (store.Status, error) {
+	var names []string
+	for n := range p.accounts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	r := store.NewDetailedReport(true)
+	if p.dryRun {
+		r.AddOK("dry run - the store and keystore were not changed")
+		r.AddOK("would reissue operator %q with a generated key", p.claim.Name)
+		for _, n := range names {
+			r.AddOK("would re-sign account %q", n)
+		}
+		return r, nil
+	}
+
+	kp, err := nkeys.CreateOperator()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	ks := ctx.StoreCtx().KeyStore
+	if _, err := ks.Store(kp); err != nil {
+		return nil, err
+	}
+	old := p.claim.Subject
+	r.AddOK("generated and stored operator key %q", pk)
+
+	p.claim.Subject = pk
+	token, err := p.claim.Encode(kp)
+	if err != nil {
+		return nil, err
+	}
+	s := ctx.StoreCtx().Store
+	if _, err := s.StoreClaim([]byte(token)); err != nil {
+		r.AddError("error storing operator %q: %v", p.claim.Name, err)
+		return r, nil
+	}
+	r.AddOK("reissued operator %q - its identity changed from %q to %q", p.claim.Name, old, pk)
+
+	for _, n := range names {
+		token, err := p.accounts[n].Encode(kp)
+		if err != nil {
+			r.AddError("error re-signing account %q: %v", n, err)
+			continue
+		}
+		ar := store.NewReport(store.OK, "re-signed account %q", n)
+		StoreAccountAndUpdateStatus(ctx, token, ar)
+		r.Add(ar)
+	}
+	if r.HasErrors() {
+		return r, nil
+	}
+	r.AddWarning("servers trusting the old operator key %q must be configured with the new operator jwt", old)
+	return r, nil
+}
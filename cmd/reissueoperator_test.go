@@ -0,0 +1,73 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReissueOperator(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddAccount(t, "B")
+	oc, err := ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	old := oc.Subject
+
+	_, stderr, err := ExecuteCmd(createReissueOperatorCmd(), "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `would re-sign account "A"`)
+	require.Contains(t, stderr, `would re-sign account "B"`)
+	oc, err = ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	require.Equal(t, old, oc.Subject)
+
+	_, stderr, err = ExecuteCmd(createReissueOperatorCmd())
+	require.NoError(t, err)
+	require.Contains(t, stderr, `re-signed account "A"`)
+	require.Contains(t, stderr, `re-signed account "B"`)
+
+	oc, err = ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	require.NotEqual(t, old, oc.Subject)
+	require.Equal(t, oc.Subject, oc.Issuer)
+	require.True(t, ts.KeyStore.HasPrivateKey(oc.Subject))
+	for _, n := range []string{"A", "B"} {
+		ac, err := ts.Store.ReadAccountClaim(n)
+		require.NoError(t, err)
+		require.Equal(t, oc.Subject, ac.Issuer)
+		require.True(t, oc.DidSign(ac))
+	}
+}
+
+func Test_ReissueOperatorRequiresKey(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	oc, err := ts.Store.ReadOperatorClaim()
+	require.NoError(t, err)
+	require.NoError(t, ts.KeyStore.Remove(oc.Subject))
+
+	_, _, err = ExecuteCmd(createReissueOperatorCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not in the keystore")
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, oc.Subject, ac.Issuer)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createRevokeExportCmd() *cobra.Command {
+	var params RevokeExportParams
+	cmd := &cobra.Command{
+		Use:          "export",
+		Short:        "Write the user revocations of an account as json",
+		Example:      "nsc revocations export --account A --output-file revocations.json",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.outputFile, "output-file", "o", "--", "output file, '--' is stdout")
+	params.AccountContextParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	revokeCmd.AddCommand(createRevokeExportCmd())
+}
+
+// RevokeExportParams writes the revocations of an account as a json
+// object mapping the public key to the unix time of the revocation
+type RevokeExportParams struct {
+	AccountContextParams
+	outputFile string
+	claim      *jwt.AccountClaims
+}
+
+func (p *RevokeExportParams) SetDefaults(ctx ActionCtx) error {
+	p.AccountContextParams.SetDefaults(ctx)
+	return nil
+}
+
+func (p *RevokeExportParams) PreInteractive(ctx ActionCtx) error {
+	return p.AccountContextParams.Edit(ctx)
+}
+
+func (p *RevokeExportParams) Load(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	return err
+}
+
+func (p *RevokeExportParams) Validate(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *RevokeExportParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *RevokeExportParams) Run(ctx ActionCtx) (store.Status, error) {
+	revocations := p.claim.Revocations
+	if revocations == nil {
+		revocations = jwt.RevocationList{}
+	}
+	d, err := json.MarshalIndent(revocations, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	d = append(d, '\n')
+	if err := Write(p.outputFile, d); err != nil {
+		return nil, err
+	}
+	if IsStdOut(p.outputFile) {
+		return nil, nil
+	}
+	return store.OKStatus("wrote %d revocations to %q", len(revocations), AbbrevHomePaths(p.outputFile)), nil
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createRevokeImportCmd() *cobra.Command {
+	var params RevokeImportParams
+	cmd := &cobra.Command{
+		Use:          "import",
+		Short:        "Merge user revocations written by 'revocations export' into an account",
+		Example:      "nsc revocations import --account A --file revocations.json",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.file, "file", "f", "", "json file with the revocations")
+	params.AccountContextParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	revokeCmd.AddCommand(createRevokeImportCmd())
+}
+
+// RevokeImportParams merges revocations into an account, when a key is
+// already revoked the latest revocation time is kept
+type RevokeImportParams struct {
+	AccountContextParams
+	SignerParams
+	file        string
+	revocations jwt.RevocationList
+	claim       *jwt.AccountClaims
+}
+
+func (p *RevokeImportParams) SetDefaults(ctx ActionCtx) error {
+	p.AccountContextParams.SetDefaults(ctx)
+	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
+	return nil
+}
+
+func (p *RevokeImportParams) PreInteractive(ctx ActionCtx) error {
+	if err := p.AccountContextParams.Edit(ctx); err != nil {
+		return err
+	}
+	return p.SignerParams.Edit(ctx)
+}
+
+func (p *RevokeImportParams) Load(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	if p.file == "" {
+		ctx.CurrentCmd().SilenceUsage = false
+		return errors.New("--file is required")
+	}
+	d, err := Read(p.file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(d, &p.revocations); err != nil {
+		return fmt.Errorf("error parsing revocations %q: %v", p.file, err)
+	}
+	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	return err
+}
+
+func (p *RevokeImportParams) Validate(ctx ActionCtx) error {
+	for k := range p.revocations {
+		if !nkeys.IsValidPublicUserKey(k) && !nkeys.IsValidPublicAccountKey(k) {
+			return fmt.Errorf("%q is not a valid account or user public key", k)
+		}
+	}
+	return p.SignerParams.Resolve(ctx)
+}
+
+func (p *RevokeImportParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *RevokeImportParams) Run(ctx ActionCtx) (store.Status, error) {
+	var keys []string
+	for k := range p.revocations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	r := store.NewDetailedReport(true)
+	for _, k := range keys {
+		at := p.revocations[k]
+		if old, ok := p.claim.Revocations[k]; ok && old >= at {
+			r.AddOK("kept revocation of %s at %d", k, old)
+			continue
+		}
+		p.claim.RevokeAt(k, time.Unix(at, 0))
+		r.AddOK("revoked %s at %d", k, at)
+	}
+
+	token, err := p.claim.Encode(p.signerKP)
+	if err != nil {
+		return nil, err
+	}
+	StoreAccountAndUpdateStatus(ctx, token, r)
+	return r, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeExportImport(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	_, pk1, _ := CreateUserKey(t)
+	_, pk2, _ := CreateUserKey(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createRevokeUserCmd(), "--public-key", pk1, "--at", "1000")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--public-key", pk2, "--at", "2000")
+	require.NoError(t, err)
+
+	fp := filepath.Join(ts.Dir, "revocations.json")
+	_, _, err = ExecuteCmd(createRevokeExportCmd(), "--account", "A", "--output-file", fp)
+	require.NoError(t, err)
+	d, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	var exported map[string]int64
+	require.NoError(t, json.Unmarshal(d, &exported))
+	require.Equal(t, map[string]int64{pk1: 1000, pk2: 2000}, exported)
+
+	ts.AddAccount(t, "B")
+	// older than the export for pk1, newer for pk2
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--account", "B", "--public-key", pk1, "--at", "500")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--account", "B", "--public-key", pk2, "--at", "3000")
+	require.NoError(t, err)
+
+	_, _, err = ExecuteCmd(createRevokeImportCmd(), "--account", "B", "--file", fp)
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("B")
+	require.NoError(t, err)
+	require.Len(t, ac.Revocations, 2)
+	require.Equal(t, int64(1000), ac.Revocations[pk1])
+	require.Equal(t, int64(3000), ac.Revocations[pk2])
+}
+
+func TestRevokeImportBadKey(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	fp := filepath.Join(ts.Dir, "revocations.json")
+	require.NoError(t, ioutil.WriteFile(fp, []byte(`{"OBAD": 1000}`), 0600))
+	_, _, err := ExecuteCmd(createRevokeImportCmd(), "--file", fp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"OBAD" is not a valid account or user public key`)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Empty(t, ac.Revocations)
+}
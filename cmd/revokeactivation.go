@@ -40,7 +40,9 @@ func createRevokeActivationCmd() *cobra.Command {
 			}
 
 			if !QuietMode() {
-				cmd.Printf("Revoked account %s from export %s\n", params.accountKey.publicKey, params.export.Subject)
+				for _, e := range params.targets() {
+					cmd.Printf("Revoked account %s from export %s\n", params.accountKey.publicKey, e.Subject)
+				}
 			}
 			return nil
 		},
@@ -49,6 +51,7 @@ func createRevokeActivationCmd() *cobra.Command {
 	cmd.Flags().IntVarP(&params.at, "at", "", 0, "revokes all user credentials created before a Unix timestamp ('0' is treated as now)")
 	cmd.Flags().StringVarP(&params.subject, "subject", "s", "", "export subject")
 	cmd.Flags().BoolVarP(&params.service, "service", "", false, "service")
+	cmd.Flags().BoolVarP(&params.allExports, "all-exports", "", false, "revoke the activation for all the stream or service exports of the account (exclusive of --subject)")
 	params.accountKey.BindFlags("target-account", "t", nkeys.PrefixByteAccount, cmd)
 
 	params.AccountContextParams.BindFlags(cmd)
@@ -70,9 +73,21 @@ type RevokeActivationParams struct {
 	at              int
 	subject         string
 	service         bool
+	allExports      bool
 	accountKey      PubKeyParams
 }
 
+// targets returns the exports the activation is revoked for
+func (p *RevokeActivationParams) targets() jwt.Exports {
+	if p.allExports {
+		return p.possibleExports
+	}
+	if p.export == nil {
+		return nil
+	}
+	return jwt.Exports{p.export}
+}
+
 func (p *RevokeActivationParams) SetDefaults(ctx ActionCtx) error {
 	p.AccountContextParams.SetDefaults(ctx)
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
@@ -139,6 +154,16 @@ func (p *RevokeActivationParams) Load(ctx ActionCtx) error {
 }
 
 func (p *RevokeActivationParams) Validate(ctx ActionCtx) error {
+	if p.allExports {
+		if p.subject != "" {
+			ctx.CurrentCmd().SilenceUsage = false
+			return fmt.Errorf("specify only one of --subject or --all-exports")
+		}
+		if err := p.accountKey.Valid(); err != nil {
+			return err
+		}
+		return p.SignerParams.Resolve(ctx)
+	}
 
 	if len(p.possibleExports) == 1 && p.subject == "" {
 		p.subject = string(p.possibleExports[0].Subject)
@@ -170,7 +195,7 @@ func (p *RevokeActivationParams) Validate(ctx ActionCtx) error {
 
 func (p *RevokeActivationParams) PostInteractive(ctx ActionCtx) error {
 	var choices []string
-	if p.subject == "" {
+	if !p.allExports && p.subject == "" {
 		for _, v := range p.possibleExports {
 			choices = append(choices, string(v.Subject))
 		}
@@ -180,13 +205,16 @@ func (p *RevokeActivationParams) PostInteractive(ctx ActionCtx) error {
 		kind = jwt.Service
 	}
 
-	i, err := cli.Select(fmt.Sprintf("select %s export", kind.String()), "", choices)
-	if err != nil {
-		return err
-	}
-	p.export = p.possibleExports[i]
-	if p.subject == "" {
-		p.subject = string(p.export.Subject)
+	var err error
+	if !p.allExports {
+		i, err := cli.Select(fmt.Sprintf("select %s export", kind.String()), "", choices)
+		if err != nil {
+			return err
+		}
+		p.export = p.possibleExports[i]
+		if p.subject == "" {
+			p.subject = string(p.export.Subject)
+		}
 	}
 
 	if err = p.accountKey.Edit(); err != nil {
@@ -207,14 +235,17 @@ func (p *RevokeActivationParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *RevokeActivationParams) Run(ctx ActionCtx) (store.Status, error) {
-	if p.export == nil {
+	exports := p.targets()
+	if len(exports) == 0 {
 		return nil, fmt.Errorf("unable to locate export")
 	}
 
-	if p.at == 0 {
-		p.export.Revoke(p.accountKey.publicKey)
-	} else {
-		p.export.RevokeAt(p.accountKey.publicKey, time.Unix(int64(p.at), 0))
+	for _, e := range exports {
+		if p.at == 0 {
+			e.Revoke(p.accountKey.publicKey)
+		} else {
+			e.RevokeAt(p.accountKey.publicKey, time.Unix(int64(p.at), 0))
+		}
 	}
 
 	token, err := p.claim.Encode(p.signerKP)
@@ -224,7 +255,9 @@ func (p *RevokeActivationParams) Run(ctx ActionCtx) (store.Status, error) {
 	r := store.NewDetailedReport(true)
 	StoreAccountAndUpdateStatus(ctx, token, r)
 	if r.HasNoErrors() {
-		r.AddOK("revoked activation %s for account %s", p.export.Name, p.accountKey.publicKey)
+		for _, e := range exports {
+			r.AddOK("revoked activation %s for account %s", e.Name, p.accountKey.publicKey)
+		}
 	}
 	return r, nil
 }
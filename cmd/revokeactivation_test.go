@@ -157,3 +157,34 @@ func TestRevokeActivationForServiceInteractive(t *testing.T) {
 		require.False(t, exp.IsRevokedAt(pub, time.Unix(1001, 0)))
 	}
 }
+
+func TestRevokeActivationAllExports(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddExport(t, "A", jwt.Stream, "foo.>", false)
+	ts.AddExport(t, "A", jwt.Stream, "bar.>", false)
+	ts.AddExport(t, "A", jwt.Stream, "baz", false)
+	ts.AddExport(t, "A", jwt.Service, "svc", false)
+
+	_, pub, _ := CreateAccountKey(t)
+
+	_, _, err := ExecuteCmd(createRevokeActivationCmd(), "--all-exports", "--target-account", pub, "--at", "1000")
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Exports, 4)
+	for _, exp := range ac.Exports {
+		if exp.Type == jwt.Service {
+			require.False(t, exp.IsRevokedAt(pub, time.Unix(999, 0)))
+			continue
+		}
+		require.True(t, exp.IsRevokedAt(pub, time.Unix(999, 0)))
+		require.False(t, exp.IsRevokedAt(pub, time.Unix(1001, 0)))
+	}
+
+	_, _, err = ExecuteCmd(createRevokeActivationCmd(), "--all-exports", "--subject", "baz", "--target-account", pub)
+	require.Error(t, err)
+}
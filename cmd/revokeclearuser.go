@@ -17,7 +17,7 @@ package cmd
 
 import (
 	"fmt"
-	"time"
+	"sort"
 
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nkeys"
@@ -37,13 +37,15 @@ func createClearRevokeUserCmd() *cobra.Command {
 				return err
 			}
 
-			if !QuietMode() {
-				cmd.Printf("Cleared revocation of user %s with public key %s\n", params.user, params.userPubKey)
+			if !QuietMode() && params.user != "" {
+				cmd.Printf("Cleared revocation of user %s with public key %s\n", params.user, params.cleared[0])
 			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&params.user, "name", "n", "", "user name")
+	cmd.Flags().StringVarP(&params.userPubKey, "public-key", "", "", "public key of the user to clear the revocation for")
+	cmd.Flags().BoolVarP(&params.all, "all", "", false, "clear all the user revocations in the account")
 	params.AccountContextParams.BindFlags(cmd)
 
 	return cmd
@@ -58,6 +60,8 @@ type ClearRevokeUserParams struct {
 	AccountContextParams
 	user       string
 	userPubKey string
+	all        bool
+	cleared    []string
 	claim      *jwt.AccountClaims
 	SignerParams
 }
@@ -65,6 +69,16 @@ type ClearRevokeUserParams struct {
 func (p *ClearRevokeUserParams) SetDefaults(ctx ActionCtx) error {
 	p.AccountContextParams.SetDefaults(ctx)
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
+	n := 0
+	for _, set := range []bool{p.user != "", p.userPubKey != "", p.all} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("specify only one of --name, --public-key or --all")
+	}
 	return nil
 }
 
@@ -74,7 +88,7 @@ func (p *ClearRevokeUserParams) PreInteractive(ctx ActionCtx) error {
 	if err = p.AccountContextParams.Edit(ctx); err != nil {
 		return err
 	}
-	if p.user == "" {
+	if p.user == "" && p.userPubKey == "" && !p.all {
 		p.user, err = ctx.StoreCtx().PickUser(p.AccountContextParams.Name)
 		if err != nil {
 			return err
@@ -93,6 +107,30 @@ func (p *ClearRevokeUserParams) Load(ctx ActionCtx) error {
 		return err
 	}
 
+	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+
+	if p.userPubKey != "" {
+		if !nkeys.IsValidPublicUserKey(p.userPubKey) {
+			return fmt.Errorf("%q is not a valid user public key", p.userPubKey)
+		}
+		p.cleared = []string{p.userPubKey}
+		return nil
+	}
+
+	if p.all {
+		if len(p.claim.Revocations) == 0 {
+			return fmt.Errorf("no revocations found in account %q", p.AccountContextParams.Name)
+		}
+		for k := range p.claim.Revocations {
+			p.cleared = append(p.cleared, k)
+		}
+		sort.Strings(p.cleared)
+		return nil
+	}
+
 	if p.user == "" {
 		n := ctx.StoreCtx().DefaultUser(p.AccountContextParams.Name)
 		if n != nil {
@@ -104,11 +142,6 @@ func (p *ClearRevokeUserParams) Load(ctx ActionCtx) error {
 		return fmt.Errorf("user is required")
 	}
 
-	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
-	if err != nil {
-		return err
-	}
-
 	userClaim, err := ctx.StoreCtx().Store.ReadUserClaim(p.AccountContextParams.Name, p.user)
 	if err != nil {
 		return err
@@ -118,7 +151,7 @@ func (p *ClearRevokeUserParams) Load(ctx ActionCtx) error {
 		return fmt.Errorf("user is required")
 	}
 
-	p.userPubKey = userClaim.Subject
+	p.cleared = []string{userClaim.Subject}
 
 	return nil
 }
@@ -137,11 +170,12 @@ func (p *ClearRevokeUserParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *ClearRevokeUserParams) Run(ctx ActionCtx) (store.Status, error) {
-	if !p.claim.IsRevokedAt(p.userPubKey, time.Unix(0, 0)) {
-		return nil, fmt.Errorf("user with public key %s is not revoked", p.userPubKey)
+	for _, pk := range p.cleared {
+		if _, ok := p.claim.Revocations[pk]; !ok {
+			return nil, fmt.Errorf("no revocation found for %s", pk)
+		}
+		p.claim.ClearRevocation(pk)
 	}
-
-	p.claim.ClearRevocation(p.userPubKey)
 	token, err := p.claim.Encode(p.signerKP)
 	if err != nil {
 		return nil, err
@@ -149,7 +183,9 @@ func (p *ClearRevokeUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	r := store.NewDetailedReport(true)
 	StoreAccountAndUpdateStatus(ctx, token, r)
 	if r.HasNoErrors() {
-		r.AddOK("cleared user revocation for account %s", p.userPubKey)
+		for _, pk := range p.cleared {
+			r.AddOK("cleared user revocation for account %s", pk)
+		}
 	}
 	return r, nil
 }
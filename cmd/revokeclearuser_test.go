@@ -83,3 +83,41 @@ func TestRevokeClearUserInteractive(t *testing.T) {
 	_, _, err = ExecuteInteractiveCmd(cmd, input, "-i")
 	require.NoError(t, err)
 }
+
+func TestRevokeClearUserAll(t *testing.T) {
+	ts := NewTestStore(t, "revoke_clear_user")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "one")
+	ts.AddUser(t, "A", "two")
+	_, pub, _ := CreateUserKey(t)
+
+	_, _, err := ExecuteCmd(createRevokeUserCmd(), "--all")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--public-key", pub)
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Revocations, 3)
+
+	_, _, err = ExecuteCmd(createClearRevokeUserCmd(), "--public-key", pub)
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Revocations, 2)
+
+	_, _, err = ExecuteCmd(createClearRevokeUserCmd(), "--public-key", pub)
+	require.Error(t, err)
+	require.Equal(t, "no revocation found for "+pub, err.Error())
+
+	_, _, err = ExecuteCmd(createClearRevokeUserCmd(), "--all")
+	require.NoError(t, err)
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Empty(t, ac.Revocations)
+
+	_, _, err = ExecuteCmd(createClearRevokeUserCmd(), "--all")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no revocations found")
+}
@@ -39,6 +39,8 @@ func createRevokeUserCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&params.user, "name", "n", "", "user name")
+	cmd.Flags().StringVarP(&params.userPubKey, "public-key", "", "", "public key of the user to revoke, the user doesn't need to be in the account")
+	cmd.Flags().BoolVarP(&params.all, "all", "", false, "revoke all the users in the account")
 	cmd.Flags().IntVarP(&params.at, "at", "", 0, "revokes all user credentials created before a Unix timestamp ('0' is treated as now)")
 
 	params.AccountContextParams.BindFlags(cmd)
@@ -56,6 +58,8 @@ type RevokeUserParams struct {
 	at         int
 	user       string
 	userPubKey string
+	all        bool
+	revoked    []string
 	claim      *jwt.AccountClaims
 	SignerParams
 }
@@ -63,6 +67,16 @@ type RevokeUserParams struct {
 func (p *RevokeUserParams) SetDefaults(ctx ActionCtx) error {
 	p.AccountContextParams.SetDefaults(ctx)
 	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
+	n := 0
+	for _, set := range []bool{p.user != "", p.userPubKey != "", p.all} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		ctx.CurrentCmd().SilenceUsage = false
+		return fmt.Errorf("specify only one of --name, --public-key or --all")
+	}
 	return nil
 }
 
@@ -80,7 +94,7 @@ func (p *RevokeUserParams) PreInteractive(ctx ActionCtx) error {
 	if err = p.AccountContextParams.Edit(ctx); err != nil {
 		return err
 	}
-	if p.user == "" {
+	if p.user == "" && p.userPubKey == "" && !p.all {
 		p.user, err = ctx.StoreCtx().PickUser(p.AccountContextParams.Name)
 		if err != nil {
 			return err
@@ -104,6 +118,23 @@ func (p *RevokeUserParams) Load(ctx ActionCtx) error {
 		return err
 	}
 
+	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+
+	if p.userPubKey != "" {
+		if !nkeys.IsValidPublicUserKey(p.userPubKey) {
+			return fmt.Errorf("%q is not a valid user public key", p.userPubKey)
+		}
+		p.revoked = []string{p.userPubKey}
+		return nil
+	}
+
+	if p.all {
+		return p.loadAllUsers(ctx)
+	}
+
 	if p.user == "" {
 		n := ctx.StoreCtx().DefaultUser(p.AccountContextParams.Name)
 		if n != nil {
@@ -115,11 +146,6 @@ func (p *RevokeUserParams) Load(ctx ActionCtx) error {
 		return fmt.Errorf("user is required")
 	}
 
-	p.claim, err = ctx.StoreCtx().Store.ReadAccountClaim(p.AccountContextParams.Name)
-	if err != nil {
-		return err
-	}
-
 	userClaim, err := ctx.StoreCtx().Store.ReadUserClaim(p.AccountContextParams.Name, p.user)
 	if err != nil {
 		return err
@@ -129,8 +155,27 @@ func (p *RevokeUserParams) Load(ctx ActionCtx) error {
 		return fmt.Errorf("user is required")
 	}
 
-	p.userPubKey = userClaim.Subject
+	p.revoked = []string{userClaim.Subject}
+
+	return nil
+}
 
+func (p *RevokeUserParams) loadAllUsers(ctx ActionCtx) error {
+	s := ctx.StoreCtx().Store
+	users, err := s.ListEntries(store.Accounts, p.AccountContextParams.Name, store.Users)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("account %q has no users", p.AccountContextParams.Name)
+	}
+	for _, u := range users {
+		uc, err := s.ReadUserClaim(p.AccountContextParams.Name, u)
+		if err != nil {
+			return err
+		}
+		p.revoked = append(p.revoked, uc.Subject)
+	}
 	return nil
 }
 
@@ -148,10 +193,12 @@ func (p *RevokeUserParams) PostInteractive(ctx ActionCtx) error {
 }
 
 func (p *RevokeUserParams) Run(ctx ActionCtx) (store.Status, error) {
-	if p.at == 0 {
-		p.claim.Revoke(p.userPubKey)
-	} else {
-		p.claim.RevokeAt(p.userPubKey, time.Unix(int64(p.at), 0))
+	for _, pk := range p.revoked {
+		if p.at == 0 {
+			p.claim.Revoke(pk)
+		} else {
+			p.claim.RevokeAt(pk, time.Unix(int64(p.at), 0))
+		}
 	}
 
 	token, err := p.claim.Encode(p.signerKP)
@@ -162,7 +209,9 @@ func (p *RevokeUserParams) Run(ctx ActionCtx) (store.Status, error) {
 	r := store.NewDetailedReport(true)
 	StoreAccountAndUpdateStatus(ctx, token, r)
 	if r.HasNoErrors() {
-		r.AddOK("revoked user %s", p.userPubKey)
+		for _, pk := range p.revoked {
+			r.AddOK("revoked user %s", pk)
+		}
 	}
 	return r, nil
 }
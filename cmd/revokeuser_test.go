@@ -144,3 +144,51 @@ func TestRevokeUserInteractive(t *testing.T) {
 	require.True(t, ac.IsRevokedAt(u.Subject, time.Unix(0, 0)))
 	require.False(t, ac.IsRevokedAt(u.Subject, time.Now().Add(1*time.Hour)))
 }
+
+func TestRevokeUserPublicKeyAt(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	// the user doesn't have to be in the account
+	_, pub, _ := CreateUserKey(t)
+
+	_, _, err := ExecuteCmd(createRevokeUserCmd(), "--public-key", pub, "--at", "1000")
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Revocations, 1)
+	require.True(t, ac.IsRevokedAt(pub, time.Unix(999, 0)))
+	require.False(t, ac.IsRevokedAt(pub, time.Unix(1001, 0)))
+
+	_, apub, _ := CreateAccountKey(t)
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--public-key", apub)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a valid user public key")
+
+	_, _, err = ExecuteCmd(createRevokeUserCmd(), "--public-key", pub, "--name", "one")
+	require.Error(t, err)
+}
+
+func TestRevokeUserAll(t *testing.T) {
+	ts := NewTestStore(t, "test")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "one")
+	ts.AddUser(t, "A", "two")
+
+	_, _, err := ExecuteCmd(createRevokeUserCmd(), "--all", "--at", "1000")
+	require.NoError(t, err)
+
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.Revocations, 2)
+	for _, n := range []string{"one", "two"} {
+		u, err := ts.Store.ReadUserClaim("A", n)
+		require.NoError(t, err)
+		require.True(t, ac.IsRevokedAt(u.Subject, time.Unix(999, 0)))
+		require.False(t, ac.IsRevokedAt(u.Subject, time.Unix(1001, 0)))
+	}
+}
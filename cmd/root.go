@@ -34,6 +34,10 @@ import (
 const TestEnv = "NSC_TEST"
 
 var KeyPathFlag string
+var KeyFileFlag string
+
+// keyFileKP is the key read from --private-key-file before an action runs
+var keyFileKP nkeys.KeyPair
 var InteractiveFlag bool
 var quietMode bool
 
@@ -91,6 +95,12 @@ func ResetStore() {
 }
 
 func ResolveKeyFlag() (nkeys.KeyPair, error) {
+	if KeyFileFlag != "" {
+		if keyFileKP != nil {
+			return keyFileKP, nil
+		}
+		return store.ResolveKeyFile(KeyFileFlag)
+	}
 	if KeyPathFlag != "" {
 		kp, err := store.ResolveKey(KeyPathFlag)
 		if err != nil {
@@ -101,6 +111,37 @@ func ResolveKeyFlag() (nkeys.KeyPair, error) {
 	return nil, nil
 }
 
+// resolvePrivateKeyFlags checks the key flags before an action runs, the
+// key in a --private-key-file is read once and used by the signer.
+func resolvePrivateKeyFlags(cmd *cobra.Command) error {
+	keyFileKP = nil
+	if KeyFileFlag == "" {
+		if KeyPathFlag != "" && !isInlineKey(KeyPathFlag) {
+			if f := cmd.Flag("private-key"); f != nil && f.Changed {
+				cmd.Printf("--private-key with a path is deprecated - use --private-key-file %s\n", KeyPathFlag)
+			}
+		}
+		return nil
+	}
+	if f := cmd.Flag("private-key"); f != nil && f.Changed {
+		return errors.New("specify only one of --private-key or --private-key-file")
+	}
+	fp, err := Expand(KeyFileFlag)
+	if err != nil {
+		return err
+	}
+	keyFileKP, err = store.ResolveKeyFile(fp)
+	return err
+}
+
+func isInlineKey(v string) bool {
+	if _, err := nkeys.FromSeed([]byte(v)); err == nil {
+		return true
+	}
+	_, err := nkeys.FromPublicKey(v)
+	return err == nil
+}
+
 func GetRootCmd() *cobra.Command {
 	return rootCmd
 }
@@ -167,7 +208,9 @@ func init() {
 // hostFlags adds persistent flags that would be added by the cobra framework
 // but are not because the unit tests are testing the command directly
 func HoistRootFlags(cmd *cobra.Command) *cobra.Command {
-	cmd.PersistentFlags().StringVarP(&KeyPathFlag, "private-key", "K", "", "private key")
+	cmd.PersistentFlags().StringVarP(&KeyPathFlag, "private-key", "K", "", "private key - an inline seed, use --private-key-file for a path")
+	cmd.PersistentFlags().StringVarP(&KeyFileFlag, "private-key-file", "", "", "path to a file holding the private key (exclusive of --private-key)")
+	cmd.PersistentFlags().StringVarP(&ExternalSignerFlag, "external-signer", "", "", "sign with a command given the path of a file to sign, it prints the base64 signature - --private-key can name the signer's public key")
 	cmd.PersistentFlags().BoolVarP(&InteractiveFlag, "interactive", "i", false, "ask questions for various settings")
 	return cmd
 }
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createRotateSigningKeyCmd() *cobra.Command {
+	var params RotateSigningKeyParams
+	cmd := &cobra.Command{
+		Use:   "signing-key",
+		Short: "Replace an account signing key with a generated one",
+		Example: `nsc rotate signing-key --account A
+nsc rotate signing-key --account A --sk <old signing key> --remove-old --resign-users`,
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAction(cmd, args, &params)
+		},
+	}
+	cmd.Flags().StringVarP(&params.old, "sk", "", "", "the signing key to rotate, required if the account has more than one")
+	cmd.Flags().BoolVarP(&params.removeOld, "remove-old", "", false, "remove the old signing key from the account")
+	cmd.Flags().BoolVarP(&params.resignUsers, "resign-users", "", false, "re-sign the users issued by the old signing key with the new one")
+	params.AccountContextParams.BindFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rotateCmd.AddCommand(createRotateSigningKeyCmd())
+}
+
+// RotateSigningKeyParams adds a generated signing key to an account,
+// optionally replacing the old key and moving its users to the new key
+type RotateSigningKeyParams struct {
+	AccountContextParams
+	SignerParams
+	old         string
+	removeOld   bool
+	resignUsers bool
+	claim       *jwt.AccountClaims
+	users       map[string]*jwt.UserClaims
+}
+
+func (p *RotateSigningKeyParams) SetDefaults(ctx ActionCtx) error {
+	p.AccountContextParams.SetDefaults(ctx)
+	p.SignerParams.SetDefaults(nkeys.PrefixByteOperator, true, ctx)
+	return nil
+}
+
+func (p *RotateSigningKeyParams) PreInteractive(ctx ActionCtx) error {
+	if err := p.AccountContextParams.Edit(ctx); err != nil {
+		return err
+	}
+	return p.SignerParams.Edit(ctx)
+}
+
+func (p *RotateSigningKeyParams) Load(ctx ActionCtx) error {
+	var err error
+	if err = p.AccountContextParams.Validate(ctx); err != nil {
+		return err
+	}
+	s := ctx.StoreCtx().Store
+	p.claim, err = s.ReadAccountClaim(p.AccountContextParams.Name)
+	if err != nil {
+		return err
+	}
+	if !p.resignUsers {
+		return nil
+	}
+	users, err := s.ListEntries(store.Accounts, p.AccountContextParams.Name, store.Users)
+	if err != nil {
+		return err
+	}
+	p.users = make(map[string]*jwt.UserClaims)
+	for _, n := range users {
+		uc, err := s.ReadUserClaim(p.AccountContextParams.Name, n)
+		if err != nil {
+			return err
+		}
+		p.users[n] = uc
+	}
+	return nil
+}
+
+func (p *RotateSigningKeyParams) PostInteractive(ctx ActionCtx) error {
+	return nil
+}
+
+func (p *RotateSigningKeyParams) Validate(ctx ActionCtx) error {
+	if p.old == "" {
+		switch len(p.claim.SigningKeys) {
+		case 0:
+			return fmt.Errorf("account %q has no signing keys - add one with `edit account --sk generate`", p.AccountContextParams.Name)
+		case 1:
+			p.old = p.claim.SigningKeys[0]
+		default:
+			ctx.CurrentCmd().SilenceUsage = false
+			return errors.New("account has more than one signing key - specify the one to rotate with --sk")
+		}
+	}
+	if !p.claim.SigningKeys.Contains(p.old) {
+		return fmt.Errorf("%q is not a signing key of account %q", p.old, p.AccountContextParams.Name)
+	}
+	return p.SignerParams.Resolve(ctx)
+}
+
+func (p *RotateSigningKeyParams) Run(ctx ActionCtx) (store.Status, error) {
+	kp, err := nkeys.CreateAccount()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	r := store.NewDetailedReport(true)
+	ks := ctx.StoreCtx().KeyStore
+	if _, err := ks.Store(kp); err != nil {
+		return nil, err
+	}
+	r.AddOK("generated and stored account signing key %q", pk)
+
+	p.claim.SigningKeys.Add(pk)
+	if p.removeOld {
+		p.claim.SigningKeys.Remove(p.old)
+		r.AddOK("removed account signing key %q", p.old)
+	}
+	token, err := p.claim.Encode(p.signerKP)
+	if err != nil {
+		return nil, err
+	}
+	StoreAccountAndUpdateStatus(ctx, token, r)
+	if r.HasErrors() || !p.resignUsers {
+		return r, nil
+	}
+
+	var names []string
+	for n, uc := range p.users {
+		if uc.Issuer == p.old {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		resignUser(ctx, r, p.AccountContextParams.Name, n, p.users[n], kp, p.claim.Subject)
+	}
+	if len(names) == 0 {
+		r.AddOK("no users were issued by %q", p.old)
+	}
+	return r, nil
+}
+
+// resignUser issues the user with kp and issuerAccount and updates its
+// creds when the user key is in the keystore. Users are stored by finding
+// the account that signed them, so the account must already be stored
+// with the new key.
+func resignUser(ctx ActionCtx, r *store.Report, account string, name string, uc *jwt.UserClaims, kp nkeys.KeyPair, issuerAccount string) {
+	uc.IssuerAccount = issuerAccount
+	token, err := uc.Encode(kp)
+	if err != nil {
+		r.AddError("error re-signing user %q: %v", name, err)
+		return
+	}
+	rs, err := ctx.StoreCtx().Store.StoreClaim([]byte(token))
+	if rs != nil {
+		r.Add(rs)
+	}
+	if err != nil {
+		r.AddError("error storing user %q: %v", name, err)
+		return
+	}
+	r.AddOK("re-signed user %q with %q", name, uc.Issuer)
+
+	ks := ctx.StoreCtx().KeyStore
+	if !ks.HasPrivateKey(uc.Subject) {
+		r.AddWarning("the private key of user %q is not in the keystore - its creds were not updated", name)
+		return
+	}
+	ukp, err := ks.GetKeyPair(uc.Subject)
+	if err != nil {
+		r.AddError("error reading the key of user %q: %v", name, err)
+		return
+	}
+	d, err := GenerateConfig(ctx.StoreCtx().Store, account, name, ukp)
+	if err != nil {
+		r.AddError("unable to generate creds for user %q: %v", name, err)
+		return
+	}
+	cp, err := ks.MaybeStoreUserCreds(account, name, d)
+	if err != nil {
+		r.AddError("error storing creds for user %q: %v", name, err)
+		return
+	}
+	r.AddOK("updated user creds file %q", AbbrevHomePaths(cp))
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RotateSigningKeyResignUsers(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", "generate")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 1)
+	old := ac.SigningKeys[0]
+
+	for _, n := range []string{"U", "V"} {
+		_, _, err = ExecuteCmd(CreateAddUserCmd(), n, "--signing-key", old)
+		require.NoError(t, err)
+	}
+	// a user issued by the account key isn't touched
+	ts.AddUser(t, "A", "W")
+
+	_, stderr, err := ExecuteCmd(createRotateSigningKeyCmd(), "--remove-old", "--resign-users")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `re-signed user "U"`)
+	require.Contains(t, stderr, `re-signed user "V"`)
+	require.NotContains(t, stderr, `re-signed user "W"`)
+
+	ac, err = ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 1)
+	sk := ac.SigningKeys[0]
+	require.NotEqual(t, old, sk)
+	require.True(t, ts.KeyStore.HasPrivateKey(sk))
+
+	for _, n := range []string{"U", "V"} {
+		uc, err := ts.Store.ReadUserClaim("A", n)
+		require.NoError(t, err)
+		require.Equal(t, sk, uc.Issuer)
+		require.Equal(t, ac.Subject, uc.IssuerAccount)
+		require.True(t, ac.DidSign(uc))
+
+		d, err := ioutil.ReadFile(ts.KeyStore.CalcUserCredsPath("A", n))
+		require.NoError(t, err)
+		token, err := jwt.ParseDecoratedJWT(d)
+		require.NoError(t, err)
+		cuc, err := jwt.DecodeUserClaims(token)
+		require.NoError(t, err)
+		require.Equal(t, sk, cuc.Issuer)
+	}
+	uc, err := ts.Store.ReadUserClaim("A", "W")
+	require.NoError(t, err)
+	require.Equal(t, ac.Subject, uc.Issuer)
+}
+
+func Test_RotateSigningKeyKeepsOld(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+
+	_, _, err := ExecuteCmd(createRotateSigningKeyCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has no signing keys")
+
+	_, _, err = ExecuteCmd(createEditAccount(), "--sk", "generate")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createRotateSigningKeyCmd())
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Len(t, ac.SigningKeys, 2)
+
+	_, _, err = ExecuteCmd(createRotateSigningKeyCmd())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "specify the one to rotate with --sk")
+
+	_, pk, _ := CreateAccountKey(t)
+	_, _, err = ExecuteCmd(createRotateSigningKeyCmd(), "--sk", pk)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a signing key of account")
+}
+
+func Test_RotateSigningKeyResignUserWithoutKey(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(createEditAccount(), "--sk", "generate")
+	require.NoError(t, err)
+	ac, err := ts.Store.ReadAccountClaim("A")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "U", "--signing-key", ac.SigningKeys[0])
+	require.NoError(t, err)
+	require.NoError(t, ts.KeyStore.Remove(ts.GetUserPublicKey(t, "A", "U")))
+
+	_, stderr, err := ExecuteCmd(createRotateSigningKeyCmd(), "--resign-users")
+	require.NoError(t, err)
+	require.Contains(t, stderr, `re-signed user "U"`)
+	require.Contains(t, stderr, `the private key of user "U" is not in the keystore - its creds were not updated`)
+}
@@ -22,6 +22,7 @@ import (
 
 	cli "github.com/nats-io/cliprompts/v2"
 	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nsc/cmd/store"
 )
 
 // SignerParams is shared UI for a signer (-K flag). The key
@@ -103,6 +104,13 @@ func (p *SignerParams) SelectFromSigners(ctx ActionCtx, signers []string) error
 
 func (p *SignerParams) Edit(ctx ActionCtx) error {
 	var err error
+	if ExternalSignerFlag != "" {
+		return nil
+	}
+	if keyFileKP != nil {
+		p.signerKP, err = p.keyFileSigner()
+		return err
+	}
 	sctx := ctx.StoreCtx()
 	p.signerKP, _ = sctx.ResolveKey(p.kind, KeyPathFlag)
 
@@ -153,6 +161,14 @@ func (p *SignerParams) Resolve(ctx ActionCtx) error {
 	}
 
 	var err error
+	if ExternalSignerFlag != "" {
+		p.signerKP, err = newExternalSigner(ctx, p.kind)
+		return err
+	}
+	if keyFileKP != nil {
+		p.signerKP, err = p.keyFileSigner()
+		return err
+	}
 	p.signerKP, err = ctx.StoreCtx().ResolveKey(p.kind, KeyPathFlag)
 	if err != nil {
 		return err
@@ -172,6 +188,15 @@ func (p *SignerParams) Resolve(ctx ActionCtx) error {
 	return err
 }
 
+// keyFileSigner returns the key read from --private-key-file if it is a
+// key of the signer's kind
+func (p *SignerParams) keyFileSigner() (nkeys.KeyPair, error) {
+	if !store.KeyPairTypeOk(p.kind, keyFileKP) {
+		return nil, fmt.Errorf("the key in %q is not an %s key", AbbrevHomePaths(KeyFileFlag), p.kind.String())
+	}
+	return keyFileKP, nil
+}
+
 func (p *SignerParams) ForceManagedAccountKey(ctx ActionCtx, kp nkeys.KeyPair) {
 	if ctx.StoreCtx().Store.IsManaged() && p.signerKP == nil {
 		p.signerKP = kp
@@ -26,19 +26,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// generateSigningKey is the signing key value that requests a new key
+const generateSigningKey = "generate"
+
 type SigningKeysParams struct {
-	flagName string
-	paths    []string
-	kind     nkeys.PrefixByte
+	flagName  string
+	paths     []string
+	kind      nkeys.PrefixByte
+	generated []nkeys.KeyPair
 }
 
 func (e *SigningKeysParams) BindFlags(flagName string, shorthand string, kind nkeys.PrefixByte, cmd *cobra.Command) {
 	e.flagName = flagName
 	e.kind = kind
-	cmd.Flags().StringSliceVarP(&e.paths, flagName, shorthand, nil, "signing key or keypath - comma separated list or option can be specified multiple times")
+	cmd.Flags().StringSliceVarP(&e.paths, flagName, shorthand, nil, "signing key or keypath, or 'generate' to create a new key - comma separated list or option can be specified multiple times")
 }
 
 func (e *SigningKeysParams) valid(s string) error {
+	if s == generateSigningKey {
+		return nil
+	}
 	_, err := e.resolve(s)
 	return err
 }
@@ -72,7 +79,21 @@ func (e *SigningKeysParams) Valid() error {
 
 func (e *SigningKeysParams) PublicKeys() ([]string, error) {
 	var keys []string
-	for _, v := range e.paths {
+	for i, v := range e.paths {
+		if v == generateSigningKey {
+			kp, err := nkeys.CreatePair(e.kind)
+			if err != nil {
+				return nil, err
+			}
+			seed, err := kp.Seed()
+			if err != nil {
+				return nil, err
+			}
+			// resolve to the same key if called again
+			e.paths[i] = string(seed)
+			e.generated = append(e.generated, kp)
+			v = e.paths[i]
+		}
 		kp, err := e.resolve(v)
 		if err != nil {
 			return nil, err
@@ -86,10 +107,24 @@ func (e *SigningKeysParams) PublicKeys() ([]string, error) {
 	return keys, nil
 }
 
+// StoreGenerated stores the keys generated by PublicKeys in the keystore
+// and returns the paths of the stored keys
+func (e *SigningKeysParams) StoreGenerated(ks *store.KeyStore) ([]string, error) {
+	var paths []string
+	for _, kp := range e.generated {
+		fp, err := ks.Store(kp)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, fp)
+	}
+	return paths, nil
+}
+
 func (e *SigningKeysParams) Edit() error {
 	// verify any keys that were added via flags
 	for i, v := range e.paths {
-		sv, err := cli.Prompt(fmt.Sprintf("path to %s nkey or nkey", e.flagName), v, cli.Val(e.valid))
+		sv, err := cli.Prompt(fmt.Sprintf("path to %s nkey, nkey or 'generate'", e.flagName), v, cli.Val(e.valid))
 		if err != nil {
 			return err
 		}
@@ -110,7 +145,7 @@ func (e *SigningKeysParams) Edit() error {
 		if !ok {
 			break
 		}
-		sv, err := cli.Prompt(fmt.Sprintf("path to %s nkey or nkey", e.flagName), "", cli.Val(e.valid))
+		sv, err := cli.Prompt(fmt.Sprintf("path to %s nkey, nkey or 'generate'", e.flagName), "", cli.Val(e.valid))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backend holds the files of a store
+type backend interface {
+	exists(fp string) bool
+	readFile(fp string) ([]byte, error)
+	writeFile(fp string, data []byte) error
+	readDir(fp string) ([]os.FileInfo, error)
+	mkdirAll(fp string) error
+	remove(fp string) error
+}
+
+// StoreOption configures a store created by CreateStore
+type StoreOption func(s *Store)
+
+// InMemory keeps the store in memory instead of the filesystem. The
+// operators directory passed to CreateStore is only used to name paths,
+// nothing is written to disk and the store can't be loaded with LoadStore.
+func InMemory() StoreOption {
+	return func(s *Store) {
+		s.backend = newMemBackend()
+	}
+}
+
+// IsInMemory returns true if the store was created with InMemory
+func (s *Store) IsInMemory() bool {
+	_, ok := s.backend.(*memBackend)
+	return ok
+}
+
+func (s *Store) files() backend {
+	if s.backend == nil {
+		return fsBackend{}
+	}
+	return s.backend
+}
+
+type fsBackend struct{}
+
+func (fsBackend) exists(fp string) bool {
+	_, err := os.Stat(fp)
+	return !os.IsNotExist(err)
+}
+
+func (fsBackend) readFile(fp string) ([]byte, error) {
+	return ioutil.ReadFile(fp)
+}
+
+func (fsBackend) writeFile(fp string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fp), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fp, data, 0600)
+}
+
+func (fsBackend) readDir(fp string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(fp)
+}
+
+func (fsBackend) mkdirAll(fp string) error {
+	return os.MkdirAll(fp, 0700)
+}
+
+func (fsBackend) remove(fp string) error {
+	return os.Remove(fp)
+}
+
+// memBackend keeps files in a map, directories exist as long as they
+// were created or hold a file
+type memBackend struct {
+	sync.RWMutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{files: make(map[string][]byte), dirs: make(map[string]bool)}
+}
+
+func (m *memBackend) addDirs(fp string) {
+	for fp = filepath.Clean(fp); !m.dirs[fp]; fp = filepath.Dir(fp) {
+		m.dirs[fp] = true
+		if fp == filepath.Dir(fp) {
+			break
+		}
+	}
+}
+
+func (m *memBackend) exists(fp string) bool {
+	m.RLock()
+	defer m.RUnlock()
+	fp = filepath.Clean(fp)
+	_, ok := m.files[fp]
+	return ok || m.dirs[fp]
+}
+
+func (m *memBackend) readFile(fp string) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	d, ok := m.files[filepath.Clean(fp)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: fp, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), d...), nil
+}
+
+func (m *memBackend) writeFile(fp string, data []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	fp = filepath.Clean(fp)
+	if m.dirs[fp] {
+		return &os.PathError{Op: "open", Path: fp, Err: os.ErrExist}
+	}
+	m.addDirs(filepath.Dir(fp))
+	m.files[fp] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memBackend) readDir(fp string) ([]os.FileInfo, error) {
+	m.RLock()
+	defer m.RUnlock()
+	fp = filepath.Clean(fp)
+	if !m.dirs[fp] {
+		return nil, &os.PathError{Op: "open", Path: fp, Err: os.ErrNotExist}
+	}
+	var infos []os.FileInfo
+	for n, d := range m.files {
+		if filepath.Dir(n) == fp {
+			infos = append(infos, memFileInfo{name: filepath.Base(n), size: int64(len(d))})
+		}
+	}
+	for n := range m.dirs {
+		if n != fp && filepath.Dir(n) == fp {
+			infos = append(infos, memFileInfo{name: filepath.Base(n), dir: true})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+	return infos, nil
+}
+
+func (m *memBackend) mkdirAll(fp string) error {
+	m.Lock()
+	defer m.Unlock()
+	fp = filepath.Clean(fp)
+	if _, ok := m.files[fp]; ok {
+		return &os.PathError{Op: "mkdir", Path: fp, Err: os.ErrExist}
+	}
+	m.addDirs(fp)
+	return nil
+}
+
+func (m *memBackend) remove(fp string) error {
+	m.Lock()
+	defer m.Unlock()
+	fp = filepath.Clean(fp)
+	if _, ok := m.files[fp]; ok {
+		delete(m.files, fp)
+		return nil
+	}
+	if !m.dirs[fp] {
+		return &os.PathError{Op: "remove", Path: fp, Err: os.ErrNotExist}
+	}
+	prefix := fp + string(filepath.Separator)
+	for n := range m.files {
+		if strings.HasPrefix(n, prefix) {
+			return &os.PathError{Op: "remove", Path: fp, Err: os.ErrExist}
+		}
+	}
+	for n := range m.dirs {
+		if strings.HasPrefix(n, prefix) {
+			return &os.PathError{Op: "remove", Path: fp, Err: os.ErrExist}
+		}
+	}
+	delete(m.dirs, fp)
+	return nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi memFileInfo) Name() string {
+	return fi.name
+}
+
+func (fi memFileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fi memFileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0700
+	}
+	return 0600
+}
+
+func (fi memFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (fi memFileInfo) IsDir() bool {
+	return fi.dir
+}
+
+func (fi memFileInfo) Sys() interface{} {
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	okp, err := nkeys.CreateOperator()
+	require.NoError(t, err)
+	dir := filepath.Join(os.TempDir(), "nsc_memory_store_test")
+	s, err := CreateStore("", dir, &NamedKey{Name: "O", KP: okp}, InMemory())
+	require.NoError(t, err)
+	require.True(t, s.IsInMemory())
+	require.True(t, s.Has(NSCFile))
+
+	oc, err := s.ReadOperatorClaim()
+	require.NoError(t, err)
+	require.Equal(t, "O", oc.Name)
+
+	akp, err := nkeys.CreateAccount()
+	require.NoError(t, err)
+	apk, err := akp.PublicKey()
+	require.NoError(t, err)
+	ac := jwt.NewAccountClaims(apk)
+	ac.Name = "A"
+	token, err := ac.Encode(okp)
+	require.NoError(t, err)
+	_, err = s.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	ukp, err := nkeys.CreateUser()
+	require.NoError(t, err)
+	upk, err := ukp.PublicKey()
+	require.NoError(t, err)
+	uc := jwt.NewUserClaims(upk)
+	uc.Name = "U"
+	token, err = uc.Encode(akp)
+	require.NoError(t, err)
+	_, err = s.StoreClaim([]byte(token))
+	require.NoError(t, err)
+
+	accounts, err := s.ListSubContainers(Accounts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A"}, accounts)
+	rac, err := s.ReadAccountClaim("A")
+	require.NoError(t, err)
+	require.Equal(t, apk, rac.Subject)
+
+	users, err := s.ListEntries(Accounts, "A", Users)
+	require.NoError(t, err)
+	require.Equal(t, []string{"U"}, users)
+	ruc, err := s.ReadUserClaim("A", "U")
+	require.NoError(t, err)
+	require.Equal(t, upk, ruc.Subject)
+
+	require.NoError(t, s.Delete(Accounts, "A", Users, JwtName("U")))
+	require.False(t, s.Has(Accounts, "A", Users, JwtName("U")))
+	_, err = s.ReadUserClaim("A", "U")
+	require.Error(t, err)
+
+	// nothing was written to disk
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestInMemoryStoresAreIndependent(t *testing.T) {
+	okp, err := nkeys.CreateOperator()
+	require.NoError(t, err)
+	a, err := CreateStore("", "", &NamedKey{Name: "O", KP: okp}, InMemory())
+	require.NoError(t, err)
+	b, err := CreateStore("", "", &NamedKey{Name: "O", KP: okp}, InMemory())
+	require.NoError(t, err)
+
+	require.NoError(t, a.Write([]byte("a"), "file"))
+	require.True(t, a.Has("file"))
+	require.False(t, b.Has("file"))
+}
+
+func TestFilesystemStoreIsDefault(t *testing.T) {
+	okp, err := nkeys.CreateOperator()
+	require.NoError(t, err)
+	s := MakeTempStore(t, "O", okp)
+	require.False(t, s.IsInMemory())
+	require.FileExists(t, filepath.Join(s.Dir, NSCFile))
+}
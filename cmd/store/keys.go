@@ -75,6 +75,22 @@ func ResolveKey(value string) (nkeys.KeyPair, error) {
 	return kp, nil
 }
 
+// ResolveKeyFile reads a seed or public key from the file at path, unlike
+// ResolveKey the value is never interpreted as a key
+func ResolveKeyFile(path string) (nkeys.KeyPair, error) {
+	if path == "" {
+		return nil, nil
+	}
+	kp, err := keyFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file %q: %v", path, err)
+	}
+	if kp == nil {
+		return nil, fmt.Errorf("key file %q - no such file or directory", path)
+	}
+	return kp, nil
+}
+
 type KeyStore struct {
 	Env string
 }
@@ -40,6 +40,8 @@ const NSCFile = ".nsc"
 
 const Users = "users"
 const Accounts = "accounts"
+const AccountDefaultsFile = "defaults.json"
+const LimitTemplatesFile = "limit_templates.json"
 
 var standardDirs = []string{Accounts}
 
@@ -51,6 +53,7 @@ type Store struct {
 	Dir            string
 	Info           Info
 	DefaultAccount string
+	backend        backend
 }
 
 type Info struct {
@@ -120,7 +123,8 @@ func SafeName(n string) string {
 
 // CreateStore creates a new Store in the specified directory.
 // CreateStore will create the necessary directories and store the public key.
-func CreateStore(env string, operatorsDir string, operator *NamedKey) (*Store, error) {
+// By default the store is on the filesystem, see InMemory.
+func CreateStore(env string, operatorsDir string, operator *NamedKey, opts ...StoreOption) (*Store, error) {
 	var err error
 
 	root := filepath.Join(operatorsDir, operator.Name)
@@ -132,14 +136,18 @@ func CreateStore(env string, operatorsDir string, operator *NamedKey) (*Store, e
 			Kind:    jwt.OperatorClaim,
 		},
 	}
+	for _, o := range opts {
+		o(s)
+	}
+	fs := s.files()
 
-	if _, err := os.Stat(root); os.IsNotExist(err) {
-		if err := os.MkdirAll(root, 0700); err != nil {
+	if !fs.exists(root) {
+		if err := fs.mkdirAll(root); err != nil {
 			return nil, err
 		}
 	}
 
-	files, err := ioutil.ReadDir(root)
+	files, err := fs.readDir(root)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +179,7 @@ func CreateStore(env string, operatorsDir string, operator *NamedKey) (*Store, e
 
 	for _, d := range standardDirs {
 		dp := s.resolve(d, "")
-		if err = os.MkdirAll(dp, 0700); err != nil {
+		if err = fs.mkdirAll(dp); err != nil {
 			return nil, fmt.Errorf("error creating %q: %v", dp, err)
 		}
 	}
@@ -236,10 +244,7 @@ func (s *Store) HasAccount(name string) bool {
 }
 
 func (s *Store) has(fp string) bool {
-	if _, err := os.Stat(fp); os.IsNotExist(err) {
-		return false
-	}
-	return true
+	return s.files().exists(fp)
 }
 
 // Read reads the specified file name or subpath from the store
@@ -247,7 +252,7 @@ func (s *Store) Read(name ...string) ([]byte, error) {
 	s.Lock()
 	defer s.Unlock()
 	fp := s.resolve(name...)
-	d, err := ioutil.ReadFile(fp)
+	d, err := s.files().readFile(fp)
 	if err != nil {
 		return nil, fmt.Errorf("error reading %q: %v", fp, err)
 	}
@@ -260,12 +265,7 @@ func (s *Store) Write(data []byte, name ...string) error {
 	defer s.Unlock()
 
 	fp := s.resolve(name...)
-	dp := filepath.Dir(fp)
-
-	if err := os.MkdirAll(dp, 0700); err != nil {
-		return err
-	}
-	return ioutil.WriteFile(fp, data, 0600)
+	return s.files().writeFile(fp, data)
 }
 
 func (s *Store) List(path ...string) ([]os.FileInfo, error) {
@@ -273,7 +273,7 @@ func (s *Store) List(path ...string) ([]os.FileInfo, error) {
 	defer s.Unlock()
 
 	fp := s.resolve(path...)
-	return ioutil.ReadDir(fp)
+	return s.files().readDir(fp)
 }
 
 // Delete the specified file name or subpath from the store
@@ -281,7 +281,7 @@ func (s *Store) Delete(name ...string) error {
 	s.Lock()
 	defer s.Unlock()
 	fp := s.resolve(name...)
-	return os.Remove(fp)
+	return s.files().remove(fp)
 }
 
 func (s *Store) ListSubContainers(name ...string) ([]string, error) {
@@ -622,6 +622,64 @@ func (s *Store) ReadRawUserClaim(accountName string, name string) ([]byte, error
 	return nil, NewUserNotExistErr(name)
 }
 
+// AccountDefaults are nsc settings for an account that are not part
+// of the account JWT, they are stored next to the account JWT.
+type AccountDefaults struct {
+	UserExpiry string `json:"user_expiry,omitempty"`
+}
+
+// ReadAccountDefaults returns the defaults for the named account, if
+// the account doesn't have any, an empty AccountDefaults is returned
+func (s *Store) ReadAccountDefaults(name string) (*AccountDefaults, error) {
+	var d AccountDefaults
+	if err := s.loadJson(&d, Accounts, name, AccountDefaultsFile); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// WriteAccountDefaults stores the defaults for the named account
+func (s *Store) WriteAccountDefaults(name string, d *AccountDefaults) error {
+	if !s.HasAccount(name) {
+		return NewAccountNotExistErr(name)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("error serializing account defaults: %v", err)
+	}
+	return s.Write(data, Accounts, name, AccountDefaultsFile)
+}
+
+// LimitTemplate is a named set of account limits that can be applied
+// to accounts in the operator
+type LimitTemplate struct {
+	Subs         int64 `json:"subs"`
+	Conn         int64 `json:"conn"`
+	LeafNodeConn int64 `json:"leaf"`
+	Imports      int64 `json:"imports"`
+	Exports      int64 `json:"exports"`
+	Data         int64 `json:"data"`
+	Payload      int64 `json:"payload"`
+}
+
+// ReadLimitTemplates returns the limit templates of the operator by name
+func (s *Store) ReadLimitTemplates() (map[string]*LimitTemplate, error) {
+	t := make(map[string]*LimitTemplate)
+	if err := s.loadJson(&t, LimitTemplatesFile); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// WriteLimitTemplates stores the limit templates of the operator
+func (s *Store) WriteLimitTemplates(t map[string]*LimitTemplate) error {
+	data, err := json.MarshalIndent(t, "", " ")
+	if err != nil {
+		return fmt.Errorf("error serializing limit templates: %v", err)
+	}
+	return s.Write(data, LimitTemplatesFile)
+}
+
 func (s *Store) LoadRootClaim() (*jwt.GenericClaims, error) {
 	fn := JwtName(s.GetName())
 	if s.Has(fn) {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	cli "github.com/nats-io/cliprompts/v2"
@@ -135,7 +136,7 @@ func ParseExpiry(s string) (int64, error) {
 		return t.Unix(), nil
 	}
 
-	re = regexp.MustCompile(`(?P<count>-?\d+)(?P<qualifier>[mhdMyw])`)
+	re = regexp.MustCompile(`^(?P<count>-?\d+)(?P<qualifier>[mhdMyw])$`)
 	m := re.FindStringSubmatch(s)
 	if m != nil {
 		v, err := strconv.ParseInt(m[1], 10, 64)
@@ -167,3 +168,46 @@ func ParseExpiry(s string) (int64, error) {
 	}
 	return 0, fmt.Errorf("couldn't parse expiry: %v", s)
 }
+
+var durationRe = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d)`)
+
+// ParseDuration parses a duration made of one or more #ms(millis), #s(econds),
+// #m(inutes), #h(ours) or #d(ays) terms, the count can be fractional (1.5h)
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	v := s
+	neg := strings.HasPrefix(v, "-")
+	if neg {
+		v = v[1:]
+	}
+	units := map[string]time.Duration{
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  24 * time.Hour,
+	}
+	var d time.Duration
+	matches := durationRe.FindAllStringSubmatchIndex(v, -1)
+	end := 0
+	for _, m := range matches {
+		if m[0] != end {
+			break
+		}
+		end = m[1]
+		count, err := strconv.ParseFloat(v[m[2]:m[3]], 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(count * float64(units[v[m[4]:m[5]]]))
+	}
+	if len(matches) == 0 || end != len(v) {
+		return 0, fmt.Errorf("couldn't parse duration %q - use #ms(millis), #s(econds), #m(inutes), #h(ours) or #d(ays)", s)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
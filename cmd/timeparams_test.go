@@ -43,6 +43,11 @@ func TestParseExpiry(t *testing.T) {
 		{"3w", time.Now().AddDate(0, 0, 7*3).Unix(), false},
 		{"2M", time.Now().AddDate(0, 2, 0).Unix(), false},
 		{"2y", time.Now().AddDate(2, 0, 0).Unix(), false},
+		{"30d", time.Now().AddDate(0, 0, 30).Unix(), false},
+		{"6M", time.Now().AddDate(0, 6, 0).Unix(), false},
+		{"30x", 0, true},
+		{"30dd", 0, true},
+		{"d30d", 0, true},
 	}
 	for _, d := range tests {
 		v, err := ParseExpiry(d.input)
@@ -59,3 +64,42 @@ func TestParseExpiry(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	type testd struct {
+		input   string
+		output  time.Duration
+		isError bool
+	}
+	tests := []testd{
+		{"", 0, false},
+		{"0", 0, false},
+		{"0s", 0, false},
+		{"250ms", 250 * time.Millisecond, false},
+		{"5s", 5 * time.Second, false},
+		{"2h", 2 * time.Hour, false},
+		{"1d", 24 * time.Hour, false},
+		{"1.5h", 90 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"-5s", -5 * time.Second, false},
+		{"1w", 0, true},
+		{"5", 0, true},
+		{"5us", 0, true},
+		{"s5", 0, true},
+		{"5s ", 0, true},
+	}
+	for _, d := range tests {
+		v, err := ParseDuration(d.input)
+		if err != nil && !d.isError {
+			t.Errorf("%s didn't expect error: %v", d.input, err)
+			continue
+		}
+		if err == nil && d.isError {
+			t.Errorf("expected error from %s", d.input)
+			continue
+		}
+		if v != d.output {
+			t.Errorf("%s expected %v but got %v", d.input, d.output, v)
+		}
+	}
+}
@@ -61,6 +61,9 @@ func ResetForTests() {
 
 func ResetSharedFlags() {
 	KeyPathFlag = ""
+	KeyFileFlag = ""
+	keyFileKP = nil
+	ExternalSignerFlag = ""
 }
 
 func NewEmptyStore(t *testing.T) *TestStore {
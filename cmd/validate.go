@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/nats-io/jwt"
 	"github.com/nats-io/nsc/cmd/store"
@@ -34,7 +35,8 @@ func createValidateCommand() *cobra.Command {
 		Example: "validate",
 		Use: `validate (current operator/current account/account users)
 validate -a <accountName> (current operator/<accountName>/account users)
-validate -A (current operator/all accounts/all users)`,
+validate -A (current operator/all accounts/all users)
+validate --operator-only (current operator)`,
 		Args: MaxArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = false
@@ -56,6 +58,8 @@ validate -A (current operator/all accounts/all users)`,
 		},
 	}
 	cmd.Flags().BoolVarP(&params.allAccounts, "all-accounts", "A", false, "validate all accounts under the current operator (exclusive of -a)")
+	cmd.Flags().StringVarP(&params.within, "within", "", "7d", "warn about claims that expire within the duration ('0' disables the warning) - #m(inutes), #h(ours), #d(ays), #w(eeks), #M(onths), #y(ears)")
+	cmd.Flags().BoolVarP(&params.operatorOnly, "operator-only", "", false, "only validate the operator and check that a system account exists (exclusive of -a and -A)")
 	params.AccountContextParams.BindFlags(cmd)
 	return cmd
 }
@@ -67,6 +71,9 @@ func init() {
 type ValidateCmdParams struct {
 	AccountContextParams
 	allAccounts        bool
+	operatorOnly       bool
+	within             string
+	expiresBy          int64
 	operator           *jwt.ValidationResults
 	accounts           []string
 	accountValidations map[string]*jwt.ValidationResults
@@ -77,6 +84,16 @@ func (p *ValidateCmdParams) SetDefaults(ctx ActionCtx) error {
 	if p.allAccounts && p.Name != "" {
 		return errors.New("specify only one of --account or --all-accounts")
 	}
+	if p.operatorOnly && (p.allAccounts || p.Name != "") {
+		return errors.New("--operator-only is exclusive of --account and --all-accounts")
+	}
+	var err error
+	if p.expiresBy, err = ParseExpiry(p.within); err != nil {
+		return fmt.Errorf("--within %q is invalid: %v", p.within, err)
+	}
+	if p.operatorOnly {
+		return nil
+	}
 
 	// if they specified an account name, this will validate it
 	if err := p.AccountContextParams.SetDefaults(ctx); err != nil {
@@ -88,7 +105,7 @@ func (p *ValidateCmdParams) SetDefaults(ctx ActionCtx) error {
 
 func (p *ValidateCmdParams) PreInteractive(ctx ActionCtx) error {
 	var err error
-	if !p.allAccounts {
+	if !p.allAccounts && !p.operatorOnly {
 		if err = p.AccountContextParams.Edit(ctx); err != nil {
 			return err
 		}
@@ -118,6 +135,12 @@ func (p *ValidateCmdParams) validateJWT(claim jwt.Claims) *jwt.ValidationResults
 	return &vr
 }
 
+// expiresSoon returns true if the claim is not yet expired but expires
+// before the --within deadline
+func (p *ValidateCmdParams) expiresSoon(c *jwt.ClaimsData) bool {
+	return p.expiresBy > 0 && c.Expires > time.Now().Unix() && c.Expires <= p.expiresBy
+}
+
 func (p *ValidateCmdParams) Validate(ctx ActionCtx) error {
 	var err error
 	oc, err := ctx.StoreCtx().Store.ReadOperatorClaim()
@@ -131,6 +154,23 @@ func (p *ValidateCmdParams) Validate(ctx ActionCtx) error {
 		}
 		p.operator.AddError("operator is not issued by operator or operator signing key")
 	}
+	if p.expiresSoon(oc.Claims()) {
+		if p.operator == nil {
+			p.operator = &jwt.ValidationResults{}
+		}
+		p.operator.AddWarning("operator expires %s", HumanizedDate(oc.Expires))
+	}
+
+	if p.operatorOnly {
+		// the operator jwt doesn't reference the system account, look for it by name
+		if !ctx.StoreCtx().Store.HasAccount(systemAccountName) {
+			if p.operator == nil {
+				p.operator = &jwt.ValidationResults{}
+			}
+			p.operator.AddWarning("system account %q not found - add it with %q", systemAccountName, "add account "+systemAccountName)
+		}
+		return nil
+	}
 
 	p.accounts, err = p.getSelectedAccounts()
 	if err != nil {
@@ -155,6 +195,12 @@ func (p *ValidateCmdParams) Validate(ctx ActionCtx) error {
 			}
 			p.accountValidations[v].AddError("Account is not issued by operator or operator signing keys")
 		}
+		if p.expiresSoon(ac.Claims()) {
+			if p.accountValidations[v] == nil {
+				p.accountValidations[v] = &jwt.ValidationResults{}
+			}
+			p.accountValidations[v].AddWarning("account expires %s", HumanizedDate(ac.Expires))
+		}
 		users, err := ctx.StoreCtx().Store.ListEntries(store.Accounts, v, store.Users)
 		if err != nil {
 			return err
@@ -179,6 +225,18 @@ func (p *ValidateCmdParams) Validate(ctx ActionCtx) error {
 				}
 				p.accountValidations[v].AddError("user %q is not issued by account or account signing keys", u)
 			}
+			if ac.IsRevokedAt(uc.Subject, time.Unix(uc.IssuedAt, 0)) {
+				if p.accountValidations[v] == nil {
+					p.accountValidations[v] = &jwt.ValidationResults{}
+				}
+				p.accountValidations[v].AddError("user %q is revoked", u)
+			}
+			if p.expiresSoon(uc.Claims()) {
+				if p.accountValidations[v] == nil {
+					p.accountValidations[v] = &jwt.ValidationResults{}
+				}
+				p.accountValidations[v].AddWarning("user %q expires %s", u, HumanizedDate(uc.Expires))
+			}
 		}
 	}
 
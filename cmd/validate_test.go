@@ -16,9 +16,11 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nsc/cmd/store"
 	"github.com/stretchr/testify/require"
@@ -197,3 +199,70 @@ func Test_ValidateInteractive(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, stderr, "Account \"B\"")
 }
+
+func Test_ValidateOperatorOnly(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	// expired users are not checked by an operator only validation
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "U", "--expiry", "2018-01-01")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createValidateCommand(), "--operator-only")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "Operator \"O\"")
+	require.Contains(t, stderr, `system account "SYS" not found`)
+	require.NotContains(t, stderr, "Account \"A\"")
+
+	ts.AddAccount(t, "SYS")
+	_, stderr, err = ExecuteCmd(createValidateCommand(), "--operator-only")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "No issues found")
+
+	_, _, err = ExecuteCmd(createValidateCommand(), "--operator-only", "--all-accounts")
+	require.Error(t, err)
+}
+
+func Test_ValidateUserExpiresWithin(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	_, _, err := ExecuteCmd(CreateAddUserCmd(), "--name", "soon", "--expiry", "3d")
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(CreateAddUserCmd(), "--name", "later", "--expiry", "30d")
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createValidateCommand())
+	require.NoError(t, err)
+	require.Contains(t, stderr, "user \"soon\" expires")
+	require.NotContains(t, stderr, "user \"later\" expires")
+
+	_, stderr, err = ExecuteCmd(createValidateCommand(), "--within", "0")
+	require.NoError(t, err)
+	require.NotContains(t, stderr, "user \"soon\" expires")
+
+	_, stderr, err = ExecuteCmd(createValidateCommand(), "--within", "60d")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "user \"later\" expires")
+
+	_, _, err = ExecuteCmd(createValidateCommand(), "--within", "bogus")
+	require.Error(t, err)
+}
+
+func Test_ValidateRevokedUser(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	ts.AddUser(t, "A", "V")
+	at := fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix())
+	_, _, err := ExecuteCmd(createRevokeUserCmd(), "--name", "U", "--at", at)
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createValidateCommand())
+	require.Error(t, err)
+	require.Contains(t, stderr, "user \"U\" is revoked")
+	require.NotContains(t, stderr, "user \"V\" is revoked")
+}
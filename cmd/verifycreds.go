@@ -0,0 +1,85 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/nats-io/jwt"
+	"github.com/spf13/cobra"
+)
+
+func createVerifyCredsCmd() *cobra.Command {
+	var expected string
+	var cmd = &cobra.Command{
+		Use:          "creds <file>",
+		Short:        "Verify that a creds file is consistent and optionally matches a key fingerprint",
+		Example:      "nsc verify creds u.creds --expect-fingerprint 3f:4a:...",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pk, err := verifyCreds(args[0])
+			if err != nil {
+				return err
+			}
+			fp, err := KeyFingerprint(pk)
+			if err != nil {
+				return err
+			}
+			if expected != "" && expected != fp {
+				return fmt.Errorf("creds user key %q has fingerprint %s - expected %s", pk, fp, expected)
+			}
+			cmd.Printf("creds %q are for user %q [%s]\n", AbbrevHomePaths(args[0]), pk, fp)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&expected, "expect-fingerprint", "", "", "fingerprint the creds user key must match")
+	return cmd
+}
+
+func init() {
+	verifyCmd.AddCommand(createVerifyCredsCmd())
+}
+
+// verifyCreds checks that the seed in the creds file matches the subject
+// of the user jwt, and returns the user public key
+func verifyCreds(fp string) (string, error) {
+	d, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return "", err
+	}
+	token, err := jwt.ParseDecoratedJWT(d)
+	if err != nil {
+		return "", err
+	}
+	uc, err := jwt.DecodeUserClaims(token)
+	if err != nil {
+		return "", fmt.Errorf("error decoding user jwt in %q: %v", AbbrevHomePaths(fp), err)
+	}
+	kp, err := jwt.ParseDecoratedUserNKey(d)
+	if err != nil {
+		return "", err
+	}
+	pk, err := kp.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	if pk != uc.Subject {
+		return "", fmt.Errorf("creds seed for %q doesn't match the user jwt subject %q", pk, uc.Subject)
+	}
+	return pk, nil
+}
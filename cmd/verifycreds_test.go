@@ -0,0 +1,44 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VerifyCredsFingerprint(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+
+	creds := ts.KeyStore.CalcUserCredsPath("A", "U")
+	fp, err := KeyFingerprint(ts.GetUserPublicKey(t, "A", "U"))
+	require.NoError(t, err)
+
+	_, stderr, err := ExecuteCmd(createVerifyCredsCmd(), creds, "--expect-fingerprint", fp)
+	require.NoError(t, err)
+	require.Contains(t, stderr, fp)
+
+	_, opk, _ := CreateUserKey(t)
+	ofp, err := KeyFingerprint(opk)
+	require.NoError(t, err)
+	_, _, err = ExecuteCmd(createVerifyCredsCmd(), creds, "--expect-fingerprint", ofp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected "+ofp)
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/nats-io/jwt"
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/spf13/cobra"
+)
+
+func createVerifyStoreCmd() *cobra.Command {
+	var signatures bool
+	var workers int
+	var cmd = &cobra.Command{
+		Use:          "store",
+		Short:        "Verify the JWTs in the store of the current operator",
+		Example:      "nsc verify store --signatures",
+		Args:         MaxArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !signatures {
+				cmd.SilenceUsage = false
+				return errors.New("specify a verification such as --signatures")
+			}
+			if workers < 1 {
+				return fmt.Errorf("workers must be at least 1 - got %d", workers)
+			}
+			config := GetConfig()
+			if config.StoreRoot == "" {
+				return errors.New("no store set - `env --store <dir>`")
+			}
+			if config.Operator == "" {
+				return errors.New("no operator set - `env --operator <name>`")
+			}
+			s, err := config.LoadStore(config.Operator)
+			if err != nil {
+				return err
+			}
+			count, failures, err := verifyStoreSignatures(s, workers)
+			if err != nil {
+				return err
+			}
+			for _, f := range failures {
+				cmd.Printf("[ERR ] %s\n", f)
+			}
+			cmd.Printf("verified %d jwts - %d failed\n", count, len(failures))
+			if len(failures) > 0 {
+				return errors.New("signature verification failed")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&signatures, "signatures", "", false, "verify the signature and issuer of every operator, account and user jwt")
+	cmd.Flags().IntVarP(&workers, "workers", "", runtime.NumCPU(), "number of jwts verified in parallel")
+	return cmd
+}
+
+func init() {
+	verifyCmd.AddCommand(createVerifyStoreCmd())
+}
+
+type signatureJob struct {
+	label  string
+	token  []byte
+	issuer *jwt.AccountClaims
+}
+
+// verifyStoreSignatures decodes every jwt in the store, which verifies the
+// signature, and checks that each was issued by its parent or one of the
+// parent's signing keys. It returns the number of jwts checked and a sorted
+// list of failures.
+func verifyStoreSignatures(s *store.Store, workers int) (int, []string, error) {
+	var failures []string
+	oc, err := s.ReadOperatorClaim()
+	if err != nil {
+		return 0, nil, err
+	}
+	if !oc.DidSign(oc) {
+		failures = append(failures, fmt.Sprintf("operator %q is not issued by the operator", oc.Name))
+	}
+	count := 1
+
+	accounts, err := s.ListSubContainers(store.Accounts)
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.Strings(accounts)
+
+	var jobs []signatureJob
+	for _, a := range accounts {
+		count++
+		raw, err := s.ReadRawAccountClaim(a)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("account %q: %v", a, err))
+			continue
+		}
+		ac, err := jwt.DecodeAccountClaims(string(raw))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("account %q: %v", a, err))
+			continue
+		}
+		if !oc.DidSign(ac) {
+			failures = append(failures, fmt.Sprintf("account %q is not issued by the operator or its signing keys", a))
+		}
+		users, err := s.ListEntries(store.Accounts, a, store.Users)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, u := range users {
+			label := fmt.Sprintf("user %q in account %q", u, a)
+			raw, err := s.ReadRawUserClaim(a, u)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", label, err))
+				continue
+			}
+			jobs = append(jobs, signatureJob{label: label, token: raw, issuer: ac})
+		}
+	}
+	count += len(jobs)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan signatureJob)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				if err := verifyUserSignature(j); err != nil {
+					mu.Lock()
+					failures = append(failures, err.Error())
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Strings(failures)
+	return count, failures, nil
+}
+
+func verifyUserSignature(j signatureJob) error {
+	uc, err := jwt.DecodeUserClaims(string(j.token))
+	if err != nil {
+		return fmt.Errorf("%s: %v", j.label, err)
+	}
+	if !j.issuer.DidSign(uc) {
+		return fmt.Errorf("%s is not issued by the account or its signing keys", j.label)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2020 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nsc/cmd/store"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VerifyStoreSignatures(t *testing.T) {
+	ts := NewTestStore(t, "O")
+	defer ts.Done(t)
+	ts.AddAccount(t, "A")
+	ts.AddUser(t, "A", "U")
+	ts.AddUser(t, "A", "V")
+
+	_, stderr, err := ExecuteCmd(createVerifyStoreCmd(), "--signatures")
+	require.NoError(t, err)
+	require.Contains(t, stderr, "verified 4 jwts - 0 failed")
+
+	// tamper with the user payload keeping the original signature
+	raw, err := ts.Store.ReadRawUserClaim("A", "V")
+	require.NoError(t, err)
+	parts := strings.Split(string(raw), ".")
+	require.Len(t, parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	payload = []byte(strings.Replace(string(payload), "\"V\"", "\"W\"", 1))
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	require.NoError(t, ts.Store.Write([]byte(strings.Join(parts, ".")), store.Accounts, "A", store.Users, store.JwtName("V")))
+
+	_, stderr, err = ExecuteCmd(createVerifyStoreCmd(), "--signatures", "--workers", "2")
+	require.Error(t, err)
+	require.Contains(t, stderr, "user \"V\" in account \"A\"")
+	require.NotContains(t, stderr, "user \"U\" in account \"A\"")
+	require.Contains(t, stderr, "verified 4 jwts - 1 failed")
+}